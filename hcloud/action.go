@@ -0,0 +1,89 @@
+package hcloud
+
+import (
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+)
+
+// waitForAction waits until the action is finished or ctx is done
+func (c *Config) waitForAction(ctx context.Context, action *hcloud.Action) error {
+	return c.waitForActions(ctx, action)
+}
+
+// waitForActions waits until all actions are finished or ctx is done. Running actions are
+// polled every PollInterval and their progress is logged at debug level. If actions fail,
+// the returned error contains all of them.
+func (c *Config) waitForActions(ctx context.Context, actions ...*hcloud.Action) error {
+	pending := make(map[int]*hcloud.Action, len(actions))
+	for _, action := range actions {
+		if action != nil {
+			pending[action.ID] = action
+		}
+	}
+
+	ticker := time.NewTicker(c.PollInterval)
+	defer ticker.Stop()
+
+	var failed []string
+	for len(pending) > 0 {
+		select {
+		case <-ctx.Done():
+			// name the actions, which were still running
+			running := make([]string, 0, len(pending))
+			for _, action := range pending {
+				running = append(running, describeAction(action))
+			}
+			sort.Strings(running)
+
+			return fmt.Errorf("Timeout while waiting for %s to finish: %s", strings.Join(running, ", "), ctx.Err())
+		case <-ticker.C:
+		}
+
+		for id, action := range pending {
+			current, _, err := c.Client.Action.GetByID(ctx, id)
+			if err != nil {
+				if ctx.Err() != nil {
+					// reported with the running actions
+					break
+				}
+				return fmt.Errorf("Error reading %s: %s", describeAction(action), err)
+			}
+			if current == nil {
+				return fmt.Errorf("Action %d (%s) not found", action.ID, action.Command)
+			}
+
+			switch current.Status {
+			case hcloud.ActionStatusRunning:
+				if current.Progress != action.Progress {
+					log.Printf("[DEBUG] %s: %d%%", describeAction(current), current.Progress)
+				}
+				pending[id] = current
+
+			case hcloud.ActionStatusSuccess:
+				log.Printf("[DEBUG] %s: finished", describeAction(current))
+				delete(pending, id)
+
+			case hcloud.ActionStatusError:
+				failed = append(failed, fmt.Sprintf("Action %d (%s) failed: %s (%s)", current.ID, current.Command, current.ErrorMessage, current.ErrorCode))
+				delete(pending, id)
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		sort.Strings(failed)
+		return errors.New(strings.Join(failed, "\n"))
+	}
+
+	return nil
+}
+
+func describeAction(action *hcloud.Action) string {
+	return fmt.Sprintf("action %d (%s)", action.ID, action.Command)
+}
@@ -0,0 +1,80 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"context"
+	"net/http"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// Config is handed to all resources and data sources as meta
+type Config struct {
+	Client        *hcloud.Client
+	ReadOnly      bool
+	PollInterval  time.Duration
+	DefaultLabels map[string]string
+}
+
+// probeSSHKey is not a valid public key, so creating it never succeeds with a read & write token
+const probeSSHKey = "terraform-provider-hcloud-token-check"
+
+// verifyTokenTimeout bounds the requests checking the token, including their retries
+const verifyTokenTimeout = 30 * time.Second
+
+func (c *Config) verifyToken() error {
+	ctx, cancel := context.WithTimeout(context.Background(), verifyTokenTimeout)
+	defer cancel()
+
+	// cheap authenticated read request
+	_, resp, err := c.Client.ServerType.List(ctx, hcloud.ServerTypeListOpts{
+		ListOpts: hcloud.ListOpts{PerPage: 1},
+	})
+	if err != nil {
+		if resp != nil && resp.StatusCode == http.StatusUnauthorized {
+			return errors.New("Hetzner Cloud Token is invalid or expired, check that it still exists in your project")
+		}
+		return fmt.Errorf("Error verifying Hetzner Cloud Token: %s", err)
+	}
+
+	// there is no endpoint telling the token's permissions, so try a write request
+	// the api rejects for read only tokens before validating its input
+	key, resp, err := c.Client.SSHKey.Create(ctx, hcloud.SSHKeyCreateOpts{
+		Name:      probeSSHKey,
+		PublicKey: probeSSHKey,
+	})
+	switch {
+	case hcloud.IsError(err, hcloud.ErrorCodeForbidden) || (resp != nil && resp.StatusCode == http.StatusForbidden):
+		c.ReadOnly = true
+	case hcloud.IsError(err, hcloud.ErrorCodeInvalidInput) || hcloud.IsError(err, hcloud.ErrorCodeUniquenessError):
+		c.ReadOnly = false
+	case err != nil:
+		// the permissions are unknown, the api rejects changes of a read only token anyway
+		log.Printf("[WARN] Could not find out whether the Hetzner Cloud Token is read only: %s", err)
+	default:
+		// never expected, but don't leave the key behind
+		_, err = c.Client.SSHKey.Delete(ctx, key)
+		if err != nil {
+			log.Printf("[WARN] Error deleting SSH key %d (%s), which was created to check the token permissions: %s", key.ID, key.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// readOnlyCustomizeDiff refuses to plan changes if the token can't write
+func readOnlyCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	c, ok := m.(*Config)
+	if !ok || !c.ReadOnly {
+		return nil
+	}
+
+	if len(d.Id()) < 1 || len(d.GetChangedKeysPrefix("")) > 0 {
+		return errors.New("Hetzner Cloud Token is read only, resources can't be created or changed")
+	}
+
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"github.com/hetznercloud/hcloud-go/hcloud"
 	"context"
 	"strconv"
+	"fmt"
 )
 
 func dataSourceHcloudDatacenter() *schema.Resource {
@@ -22,20 +23,20 @@ func dataSourceHcloudDatacenter() *schema.Resource {
 				Computed: true,
 			},
 			"location": {
-				Type: schema.TypeInt,
+				Type: schema.TypeString,
 				Computed: true,
 			},
 			"server_types_supported": {
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
-					Type:     schema.TypeInt,
+					Type:     schema.TypeString,
 				},
 				Computed: true,
 			},
 			"server_types_available": {
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
-					Type:     schema.TypeInt,
+					Type:     schema.TypeString,
 				},
 				Computed: true,
 			},
@@ -44,44 +45,47 @@ func dataSourceHcloudDatacenter() *schema.Resource {
 }
 
 func dataSourceHcloudDatacenterRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
 	var dc *hcloud.Datacenter
 	var err error
 
 	if cid, ok := d.GetOk("id"); ok {
-		id, err := strconv.Atoi(cid.(string))
+		id, err := parseID(cid.(string))
 		if err != nil {
 			return err
 		}
 
-		dc, _, err = m.(*hcloud.Client).Datacenter.GetByID(context.Background(), id)
+		dc, _, err = m.(*Config).Client.Datacenter.GetByID(ctx, id)
 	} else {
-		dc, _, err = m.(*hcloud.Client).Datacenter.Get(context.Background(), d.Get("name").(string))
+		dc, _, err = m.(*Config).Client.Datacenter.Get(ctx, d.Get("name").(string))
 	}
 
 	if err != nil {
 		return err
 	}
 
-	if dc != nil {
-		d.SetId(strconv.Itoa(dc.ID))
-		d.Set("name", dc.Name)
-		d.Set("description", dc.Description)
-		d.Set("location", dc.Location.ID)
+	if dc == nil {
+		return fmt.Errorf("Datacenter %s not found", d.Get("name").(string))
+	}
 
-		supp := make([]int, len(dc.ServerTypes.Supported))
-		for i, v := range dc.ServerTypes.Supported {
-			supp[i] = v.ID
-		}
-		d.Set("server_types_supported", supp)
+	d.SetId(strconv.Itoa(dc.ID))
+	d.Set("name", dc.Name)
+	d.Set("description", dc.Description)
+	d.Set("location", strconv.Itoa(dc.Location.ID))
 
-		avail := make([]int, len(dc.ServerTypes.Available))
-		for i, v := range dc.ServerTypes.Available {
-			avail[i] = v.ID
-		}
-		d.Set("server_types_supported", avail)
-	} else {
-		d.SetId("")
+	supp := make([]string, len(dc.ServerTypes.Supported))
+	for i, v := range dc.ServerTypes.Supported {
+		supp[i] = strconv.Itoa(v.ID)
+	}
+	d.Set("server_types_supported", supp)
+
+	avail := make([]string, len(dc.ServerTypes.Available))
+	for i, v := range dc.ServerTypes.Available {
+		avail[i] = strconv.Itoa(v.ID)
 	}
+	d.Set("server_types_available", avail)
 
 	return nil
 }
\ No newline at end of file
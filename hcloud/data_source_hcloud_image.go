@@ -2,60 +2,345 @@ package hcloud
 
 import (
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hetznercloud/hcloud-go/hcloud"
+	hcloudschema "github.com/hetznercloud/hcloud-go/hcloud/schema"
 	"context"
+	"net/url"
+	"sort"
 	"strconv"
+	"errors"
+	"fmt"
 )
 
 func dataSourceHcloudImage() *schema.Resource {
+	s := imageFilterSchema()
+	s["most_recent"] = &schema.Schema{
+		Type: schema.TypeBool,
+		Optional: true,
+		Default: false,
+	}
+	s["description"] = &schema.Schema{
+		Type: schema.TypeString,
+		Computed: true,
+	}
+	s["created"] = &schema.Schema{
+		Type: schema.TypeString,
+		Computed: true,
+	}
+	s["image_size"] = &schema.Schema{
+		Type: schema.TypeFloat,
+		Computed: true,
+	}
+	s["disk_size"] = &schema.Schema{
+		Type: schema.TypeFloat,
+		Computed: true,
+	}
+	s["deprecated"] = &schema.Schema{
+		Type: schema.TypeString,
+		Computed: true,
+	}
+	s["labels"] = &schema.Schema{
+		Type: schema.TypeMap,
+		Elem: &schema.Schema{
+			Type: schema.TypeString,
+		},
+		Computed: true,
+	}
+
 	return &schema.Resource{
 		Read: dataSourceHcloudImageRead,
 
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type: schema.TypeString,
-				Required: true,
-				ForceNew: true,
-			},
-			"description": {
-				Type: schema.TypeString,
-				Computed: true,
-			},
-			"status": {
-				Type: schema.TypeString,
-				Computed: true,
-			},
+		Schema: s,
+	}
+}
+
+// imageFilterSchema returns the filters of the image data sources. The filters are computed,
+// so the data source of a single image can report them.
+func imageFilterSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"name": {
+			Type: schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ForceNew: true,
+		},
+		"selector": {
+			Type: schema.TypeString,
+			Optional: true,
+			ForceNew: true,
+		},
+		"type": {
+			Type: schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ValidateFunc: validation.StringInSlice([]string{
+				string(hcloud.ImageTypeSystem),
+				string(hcloud.ImageTypeSnapshot),
+				string(hcloud.ImageTypeBackup),
+			}, false),
+		},
+		"status": {
+			Type: schema.TypeString,
+			Optional: true,
+			Computed: true,
+			ValidateFunc: validation.StringInSlice([]string{
+				string(hcloud.ImageStatusAvailable),
+				string(hcloud.ImageStatusCreating),
+			}, false),
+		},
+		"os_flavor": {
+			Type: schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"os_version": {
+			Type: schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"bound_to": {
+			Type: schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"created_from": {
+			Type: schema.TypeString,
+			Optional: true,
+			Computed: true,
+		},
+		"include_deprecated": {
+			Type: schema.TypeBool,
+			Optional: true,
+			Default: false,
 		},
 	}
 }
 
 func dataSourceHcloudImageRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
 	var img *hcloud.Image
 	var err error
 
+	filter, err := getImageFilter(d)
+	if err != nil {
+		return err
+	}
+
 	if cid, ok := d.GetOk("id"); ok {
-		id, err := strconv.Atoi(cid.(string))
+		id, err := parseID(cid.(string))
+		if err != nil {
+			return err
+		}
+
+		img, _, err = m.(*Config).Client.Image.GetByID(ctx, id)
+	} else if filter.nameOnly() {
+		img, _, err = m.(*Config).Client.Image.Get(ctx, filter.Name)
 		if err != nil {
 			return err
 		}
+		if img == nil {
+			return fmt.Errorf("Image %s not found", filter.Name)
+		}
 
-		img, _, err = m.(*hcloud.Client).Image.GetByID(context.Background(), id)
+		// the lookup by name also finds deprecated images
+		if !filter.match(img) {
+			return fmt.Errorf("Image %s is deprecated, set include_deprecated = true to use it", filter.Name)
+		}
+	} else if filter.empty() {
+		return errors.New("either name, selector or another filter has to be set")
 	} else {
-		img, _, err = m.(*hcloud.Client).Image.Get(context.Background(), d.Get("name").(string))
+		var imgs []*hcloud.Image
+		imgs, err = listImages(ctx, m.(*Config), filter)
+		if err != nil {
+			return err
+		}
+
+		if len(imgs) > 1 && !d.Get("most_recent").(bool) {
+			return fmt.Errorf("%d images match the filters, set most_recent = true or narrow the filters", len(imgs))
+		}
+		if len(imgs) < 1 {
+			return errors.New("No image matches the filters")
+		}
+
+		sortImagesByCreated(imgs)
+		img = imgs[0]
 	}
 
 	if err != nil {
 		return err
 	}
 
-	if img != nil {
-		d.SetId(strconv.Itoa(img.ID))
-		d.Set("name", img.Name)
-		d.Set("description", img.Description)
-		d.Set("status", img.Status)
+	if img == nil {
+		return fmt.Errorf("Image %s not found", d.Get("name").(string))
+	}
+
+	d.SetId(strconv.Itoa(img.ID))
+	d.Set("name", img.Name)
+	d.Set("description", img.Description)
+	d.Set("status", img.Status)
+	d.Set("type", img.Type)
+	d.Set("os_flavor", img.OSFlavor)
+	d.Set("os_version", img.OSVersion)
+	d.Set("created", img.Created.String())
+	d.Set("image_size", img.ImageSize)
+	d.Set("disk_size", img.DiskSize)
+	d.Set("labels", img.Labels)
+
+	if img.BoundTo != nil {
+		d.Set("bound_to", strconv.Itoa(img.BoundTo.ID))
+	} else {
+		d.Set("bound_to", "")
+	}
+	if img.CreatedFrom != nil {
+		d.Set("created_from", strconv.Itoa(img.CreatedFrom.ID))
+	} else {
+		d.Set("created_from", "")
+	}
+	if img.IsDeprecated() {
+		d.Set("deprecated", img.Deprecated.String())
 	} else {
-		d.SetId("")
+		d.Set("deprecated", "")
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// imageFilter holds the filters of the image data sources. The api filters by name, labels,
+// type, status and bound_to, the other filters are applied to the listed images.
+type imageFilter struct {
+	Name              string
+	Selector          string
+	Type              string
+	Status            string
+	OSFlavor          string
+	OSVersion         string
+	BoundTo           int
+	CreatedFrom       int
+	IncludeDeprecated bool
+}
+
+func getImageFilter(d *schema.ResourceData) (imageFilter, error) {
+	filter := imageFilter{
+		Name:              d.Get("name").(string),
+		Selector:          d.Get("selector").(string),
+		Type:              d.Get("type").(string),
+		Status:            d.Get("status").(string),
+		OSFlavor:          d.Get("os_flavor").(string),
+		OSVersion:         d.Get("os_version").(string),
+		IncludeDeprecated: d.Get("include_deprecated").(bool),
+	}
+
+	var err error
+	if v, ok := d.GetOk("bound_to"); ok {
+		filter.BoundTo, err = parseID(v.(string))
+		if err != nil {
+			return filter, fmt.Errorf("bound_to: %s", err)
+		}
+	}
+	if v, ok := d.GetOk("created_from"); ok {
+		filter.CreatedFrom, err = parseID(v.(string))
+		if err != nil {
+			return filter, fmt.Errorf("created_from: %s", err)
+		}
+	}
+
+	return filter, nil
+}
+
+// empty reports whether no filter is set
+func (f imageFilter) empty() bool {
+	return f == imageFilter{IncludeDeprecated: f.IncludeDeprecated}
+}
+
+// nameOnly reports whether only the name is set, which is looked up like before the filters
+// existed, numeric names are looked up as id
+func (f imageFilter) nameOnly() bool {
+	return len(f.Name) > 0 && f == imageFilter{Name: f.Name}
+}
+
+func (f imageFilter) query() url.Values {
+	query := url.Values{}
+	if len(f.Name) > 0 {
+		query.Set("name", f.Name)
+	}
+	if len(f.Selector) > 0 {
+		query.Set("label_selector", f.Selector)
+	}
+	if len(f.Type) > 0 {
+		query.Set("type", f.Type)
+	}
+	if len(f.Status) > 0 {
+		query.Set("status", f.Status)
+	}
+	if f.BoundTo != 0 {
+		query.Set("bound_to", strconv.Itoa(f.BoundTo))
+	}
+	if f.IncludeDeprecated {
+		query.Set("include_deprecated", "true")
+	}
+	return query
+}
+
+func (f imageFilter) match(img *hcloud.Image) bool {
+	if img.IsDeprecated() && !f.IncludeDeprecated {
+		return false
+	}
+	if len(f.OSFlavor) > 0 && img.OSFlavor != f.OSFlavor {
+		return false
+	}
+	if len(f.OSVersion) > 0 && img.OSVersion != f.OSVersion {
+		return false
+	}
+	if f.CreatedFrom != 0 && (img.CreatedFrom == nil || img.CreatedFrom.ID != f.CreatedFrom) {
+		return false
+	}
+	return true
+}
+
+// listImages lists all images, which match the filter. The hcloud go library can't ask for
+// deprecated images, so the images are listed with a request of our own.
+func listImages(ctx context.Context, config *Config, filter imageFilter) ([]*hcloud.Image, error) {
+	var imgs []*hcloud.Image
+
+	query := filter.query()
+	query.Set("per_page", "50")
+	for page := 1; page > 0; {
+		query.Set("page", strconv.Itoa(page))
+
+		// send list request
+		req, err := config.Client.NewRequest(ctx, "GET", "/images?" + query.Encode(), nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var body hcloudschema.ImageListResponse
+		resp, err := config.Client.Do(req, &body)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, i := range body.Images {
+			img := hcloud.ImageFromSchema(i)
+			if filter.match(img) {
+				imgs = append(imgs, img)
+			}
+		}
+
+		page = 0
+		if resp.Meta.Pagination != nil {
+			page = resp.Meta.Pagination.NextPage
+		}
+	}
+
+	return imgs, nil
+}
+
+// sortImagesByCreated sorts the images from the newest to the oldest
+func sortImagesByCreated(imgs []*hcloud.Image) {
+	sort.SliceStable(imgs, func(i, j int) bool {
+		return imgs[i].Created.After(imgs[j].Created)
+	})
+}
@@ -0,0 +1,142 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/hashcode"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"context"
+	"strconv"
+	"strings"
+)
+
+const (
+	imagesSortCreatedDesc = "created_desc"
+	imagesSortCreatedAsc  = "created_asc"
+)
+
+func dataSourceHcloudImages() *schema.Resource {
+	s := imageFilterSchema()
+	s["sort"] = &schema.Schema{
+		Type: schema.TypeString,
+		Optional: true,
+		Default: imagesSortCreatedDesc,
+		ValidateFunc: validation.StringInSlice([]string{
+			imagesSortCreatedDesc,
+			imagesSortCreatedAsc,
+		}, false),
+	}
+	s["limit"] = &schema.Schema{
+		Type: schema.TypeInt,
+		Optional: true,
+		ValidateFunc: validation.IntAtLeast(0),
+	}
+	s["images"] = &schema.Schema{
+		Type: schema.TypeList,
+		Computed: true,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"id": {
+					Type: schema.TypeString,
+					Computed: true,
+				},
+				"name": {
+					Type: schema.TypeString,
+					Computed: true,
+				},
+				"type": {
+					Type: schema.TypeString,
+					Computed: true,
+				},
+				"description": {
+					Type: schema.TypeString,
+					Computed: true,
+				},
+				"created": {
+					Type: schema.TypeString,
+					Computed: true,
+				},
+				"image_size": {
+					Type: schema.TypeFloat,
+					Computed: true,
+				},
+				"disk_size": {
+					Type: schema.TypeFloat,
+					Computed: true,
+				},
+				"labels": {
+					Type: schema.TypeMap,
+					Elem: &schema.Schema{
+						Type: schema.TypeString,
+					},
+					Computed: true,
+				},
+				"bound_to": {
+					Type: schema.TypeString,
+					Computed: true,
+				},
+			},
+		},
+	}
+
+	return &schema.Resource{
+		Read: dataSourceHcloudImagesRead,
+
+		Schema: s,
+	}
+}
+
+func dataSourceHcloudImagesRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	filter, err := getImageFilter(d)
+	if err != nil {
+		return err
+	}
+
+	imgs, err := listImages(ctx, m.(*Config), filter)
+	if err != nil {
+		return err
+	}
+
+	// newest first, reversed for ascending order
+	sortImagesByCreated(imgs)
+	if d.Get("sort").(string) == imagesSortCreatedAsc {
+		for i, j := 0, len(imgs)-1; i < j; i, j = i+1, j-1 {
+			imgs[i], imgs[j] = imgs[j], imgs[i]
+		}
+	}
+
+	if limit := d.Get("limit").(int); limit > 0 && len(imgs) > limit {
+		imgs = imgs[:limit]
+	}
+
+	ids := make([]string, len(imgs))
+	images := make([]map[string]interface{}, len(imgs))
+	for i, img := range imgs {
+		ids[i] = strconv.Itoa(img.ID)
+
+		var boundTo string
+		if img.BoundTo != nil {
+			boundTo = strconv.Itoa(img.BoundTo.ID)
+		}
+
+		images[i] = map[string]interface{}{
+			"id":          strconv.Itoa(img.ID),
+			"name":        img.Name,
+			"type":        string(img.Type),
+			"description": img.Description,
+			"created":     img.Created.String(),
+			"image_size":  float64(img.ImageSize),
+			"disk_size":   float64(img.DiskSize),
+			"labels":      img.Labels,
+			"bound_to":    boundTo,
+		}
+	}
+
+	// the id changes with the listed images
+	d.SetId(strconv.Itoa(hashcode.String(strings.Join(ids, ","))))
+	d.Set("images", images)
+
+	return nil
+}
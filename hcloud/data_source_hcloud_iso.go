@@ -0,0 +1,76 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"context"
+	"strconv"
+	"fmt"
+)
+
+func dataSourceHcloudISO() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceHcloudISORead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type: schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"type": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+			"deprecated": {
+				Type: schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func dataSourceHcloudISORead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	var iso *hcloud.ISO
+	var err error
+
+	if cid, ok := d.GetOk("id"); ok {
+		id, err := parseID(cid.(string))
+		if err != nil {
+			return err
+		}
+
+		iso, _, err = m.(*Config).Client.ISO.GetByID(ctx, id)
+	} else {
+		// public and private isos share one namespace, numeric names are looked up as id
+		iso, _, err = m.(*Config).Client.ISO.Get(ctx, d.Get("name").(string))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if iso == nil {
+		return fmt.Errorf("ISO %s not found", d.Get("name").(string))
+	}
+
+	d.SetId(strconv.Itoa(iso.ID))
+	d.Set("name", iso.Name)
+	d.Set("description", iso.Description)
+	d.Set("type", string(iso.Type))
+
+	if iso.IsDeprecated() {
+		d.Set("deprecated", iso.Deprecated.String())
+	} else {
+		d.Set("deprecated", "")
+	}
+
+	return nil
+}
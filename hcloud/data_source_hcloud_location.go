@@ -5,6 +5,7 @@ import (
 	"github.com/hetznercloud/hcloud-go/hcloud"
 	"context"
 	"strconv"
+	"fmt"
 )
 
 func dataSourceHcloudLocation() *schema.Resource {
@@ -14,7 +15,7 @@ func dataSourceHcloudLocation() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type: schema.TypeString,
-				Optional: true,
+				Required: true,
 				ForceNew: true,
 			},
 			"description": {
@@ -26,31 +27,34 @@ func dataSourceHcloudLocation() *schema.Resource {
 }
 
 func dataSourceHcloudLocationRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
 	var loc *hcloud.Location
 	var err error
 
 	if cid, ok := d.GetOk("id"); ok {
-		id, err := strconv.Atoi(cid.(string))
+		id, err := parseID(cid.(string))
 		if err != nil {
 			return err
 		}
 
-		loc, _, err = m.(*hcloud.Client).Location.GetByID(context.Background(), id)
+		loc, _, err = m.(*Config).Client.Location.GetByID(ctx, id)
 	} else {
-		loc, _, err = m.(*hcloud.Client).Location.Get(context.Background(), d.Get("name").(string))
+		loc, _, err = m.(*Config).Client.Location.Get(ctx, d.Get("name").(string))
 	}
 
 	if err != nil {
 		return err
 	}
 
-	if loc != nil {
-		d.SetId(strconv.Itoa(loc.ID))
-		d.Set("name", loc.Name)
-		d.Set("description", loc.Description)
-	} else {
-		d.SetId("")
+	if loc == nil {
+		return fmt.Errorf("Location %s not found", d.Get("name").(string))
 	}
 
+	d.SetId(strconv.Itoa(loc.ID))
+	d.Set("name", loc.Name)
+	d.Set("description", loc.Description)
+
 	return nil
 }
\ No newline at end of file
@@ -5,6 +5,7 @@ import (
 	"github.com/hetznercloud/hcloud-go/hcloud"
 	"context"
 	"strconv"
+	"fmt"
 )
 
 func dataSourceHcloudServertype() *schema.Resource {
@@ -14,7 +15,7 @@ func dataSourceHcloudServertype() *schema.Resource {
 		Schema: map[string]*schema.Schema{
 			"name": {
 				Type: schema.TypeString,
-				Optional: true,
+				Required: true,
 				ForceNew: true,
 			},
 			"description": {
@@ -26,31 +27,34 @@ func dataSourceHcloudServertype() *schema.Resource {
 }
 
 func dataSourceHcloudServertypeRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
 	var st *hcloud.ServerType
 	var err error
 
 	if cid, ok := d.GetOk("id"); ok {
-		id, err := strconv.Atoi(cid.(string))
+		id, err := parseID(cid.(string))
 		if err != nil {
 			return err
 		}
 
-		st, _, err = m.(*hcloud.Client).ServerType.GetByID(context.Background(), id)
+		st, _, err = m.(*Config).Client.ServerType.GetByID(ctx, id)
 	} else {
-		st, _, err = m.(*hcloud.Client).ServerType.Get(context.Background(), d.Get("name").(string))
+		st, _, err = m.(*Config).Client.ServerType.Get(ctx, d.Get("name").(string))
 	}
 
 	if err != nil {
 		return err
 	}
 
-	if st != nil {
-		d.SetId(strconv.Itoa(st.ID))
-		d.Set("name", st.Name)
-		d.Set("description", st.Description)
-	} else {
-		d.SetId("")
+	if st == nil {
+		return fmt.Errorf("Server Type %s not found", d.Get("name").(string))
 	}
 
+	d.SetId(strconv.Itoa(st.ID))
+	d.Set("name", st.Name)
+	d.Set("description", st.Description)
+
 	return nil
 }
\ No newline at end of file
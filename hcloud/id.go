@@ -0,0 +1,62 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// ids are stored as strings, since the ids of the api can exceed 32-bit integers. Ids are
+// parsed as 64-bit integers, so an id which doesn't fit into an int of this platform fails
+// with an error instead of overflowing.
+
+// parseID parses an id of the api
+func parseID(s string) (int, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid id %q: %s", s, err)
+	}
+	if int64(int(id)) != id {
+		return 0, fmt.Errorf("id %d exceeds the integers of this %d-bit build", id, strconv.IntSize)
+	}
+	return int(id), nil
+}
+
+// idStateUpgraders upgrades states of schema version 0, in which the ids in keys were stored
+// as numbers. Flatmap states of terraform 0.11 store every value as string, so they are
+// decoded with the current schema of r.
+func idStateUpgraders(r *schema.Resource, keys ...string) []schema.StateUpgrader {
+	return []schema.StateUpgrader{
+		{
+			Version: 0,
+			Type:    r.CoreConfigSchema().ImpliedType(),
+			Upgrade: func(rawState map[string]interface{}, meta interface{}) (map[string]interface{}, error) {
+				for _, k := range keys {
+					switch v := rawState[k].(type) {
+					case nil:
+					case []interface{}:
+						for i := range v {
+							v[i] = upgradeID(v[i])
+						}
+					default:
+						rawState[k] = upgradeID(v)
+					}
+				}
+				return rawState, nil
+			},
+		},
+	}
+}
+
+func upgradeID(v interface{}) interface{} {
+	switch v := v.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case json.Number:
+		return v.String()
+	case int:
+		return strconv.Itoa(v)
+	}
+	return v
+}
@@ -0,0 +1,116 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+// label keys consist of an optional prefix (a dns subdomain) and a name, divided by a slash.
+// Names and values have at most 63 characters, which are alphanumeric, '-', '_' or '.',
+// and start and end with an alphanumeric character. Values may also be empty.
+var (
+	labelPrefixRegexp = regexp.MustCompile(`^[a-z0-9]([-a-z0-9]*[a-z0-9])?(\.[a-z0-9]([-a-z0-9]*[a-z0-9])?)*$`)
+	labelNameRegexp   = regexp.MustCompile(`^[a-zA-Z0-9]([-a-zA-Z0-9_.]*[a-zA-Z0-9])?$`)
+)
+
+func validateLabels(v interface{}, k string) (ws []string, es []error) {
+	for key, value := range v.(map[string]interface{}) {
+		err := validateLabelKey(key)
+		if err != nil {
+			es = append(es, fmt.Errorf("%s: %s", k, err))
+		}
+
+		s, _ := value.(string)
+		if len(s) > 63 || (len(s) > 0 && !labelNameRegexp.MatchString(s)) {
+			es = append(es, fmt.Errorf("%s: invalid value %q of label %q, values have at most 63 characters, which are alphanumeric, '-', '_' or '.', and start and end with an alphanumeric character", k, s, key))
+		}
+	}
+	return
+}
+
+func validateLabelKey(key string) error {
+	name := key
+	if i := strings.LastIndex(key, "/"); i >= 0 {
+		prefix := key[:i]
+		name = key[i+1:]
+
+		if len(prefix) > 253 || !labelPrefixRegexp.MatchString(prefix) {
+			return fmt.Errorf("invalid label key %q, the prefix has to be a dns subdomain", key)
+		}
+	}
+
+	if len(name) > 63 || !labelNameRegexp.MatchString(name) {
+		return fmt.Errorf("invalid label key %q, names have 1 to 63 characters, which are alphanumeric, '-', '_' or '.', and start and end with an alphanumeric character", key)
+	}
+	return nil
+}
+
+// expandLabels converts the labels of the resource data for the api
+func expandLabels(labels map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(labels))
+	for k, v := range labels {
+		result[k] = v.(string)
+	}
+	return result
+}
+
+// mergeLabels merges the default labels of the provider into the labels of a resource, the
+// labels of the resource win
+func (c *Config) mergeLabels(labels map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(c.DefaultLabels)+len(labels))
+	for k, v := range c.DefaultLabels {
+		result[k] = v
+	}
+	for k, v := range expandLabels(labels) {
+		result[k] = v
+	}
+	return result
+}
+
+// defaultLabelsFor returns the default labels of the provider, which a resource with the
+// given labels should have
+func (c *Config) defaultLabelsFor(labels map[string]interface{}) map[string]string {
+	result := make(map[string]string, len(c.DefaultLabels))
+	for k, v := range c.DefaultLabels {
+		if _, ok := labels[k]; !ok {
+			result[k] = v
+		}
+	}
+	return result
+}
+
+// splitLabels splits the labels of the api into the labels of the resource and the default
+// labels of the provider. Default keys stay with the resource, if it had them before.
+func (c *Config) splitLabels(labels map[string]string, previous map[string]interface{}) (map[string]string, map[string]string) {
+	own := make(map[string]string, len(labels))
+	defaults := make(map[string]string, len(c.DefaultLabels))
+	for k, v := range labels {
+		_, isDefault := c.DefaultLabels[k]
+		_, wasOwn := previous[k]
+		if isDefault && !wasOwn {
+			defaults[k] = v
+		} else {
+			own[k] = v
+		}
+	}
+	return own, defaults
+}
+
+// labelsCustomizeDiff plans an update, if the default labels of the provider were added,
+// removed or changed their value. The default keys don't show up in the diff of labels.
+func labelsCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if !d.NewValueKnown("labels") {
+		return d.SetNewComputed("default_labels")
+	}
+
+	want := m.(*Config).defaultLabelsFor(d.Get("labels").(map[string]interface{}))
+	have := expandLabels(d.Get("default_labels").(map[string]interface{}))
+	if len(d.Id()) > 0 && reflect.DeepEqual(want, have) {
+		return nil
+	}
+
+	return d.SetNew("default_labels", want)
+}
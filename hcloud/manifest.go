@@ -0,0 +1,47 @@
+package hcloud
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// snapshotManifestEntry records a snapshot, which was taken before a server was destroyed
+type snapshotManifestEntry struct {
+	ServerID    int    `json:"server_id"`
+	ServerName  string `json:"server_name"`
+	ImageID     int    `json:"image_id"`
+	Description string `json:"description"`
+	Created     string `json:"created"`
+}
+
+// servers are destroyed in parallel, so writes to the manifest are serialized
+var snapshotManifestMu sync.Mutex
+
+// appendSnapshotManifest adds the entry to the json array in the file at path, the file is
+// created, if it doesn't exist
+func appendSnapshotManifest(path string, entry snapshotManifestEntry) error {
+	snapshotManifestMu.Lock()
+	defer snapshotManifestMu.Unlock()
+
+	var entries []snapshotManifestEntry
+	content, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(content) > 0 {
+		err = json.Unmarshal(content, &entries)
+		if err != nil {
+			return err
+		}
+	}
+
+	entries = append(entries, entry)
+	content, err = json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, append(content, '\n'), 0644)
+}
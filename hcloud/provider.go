@@ -3,6 +3,13 @@ package hcloud
 import (
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hetznercloud/hcloud-go/hcloud"
+	"github.com/mitchellh/go-homedir"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+	"errors"
+	"fmt"
 )
 
 func Provider() *schema.Provider {
@@ -10,27 +17,116 @@ func Provider() *schema.Provider {
 		Schema: map[string]*schema.Schema{
 			"token": {
 				Type:        schema.TypeString,
-				Required:    true,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HCLOUD_TOKEN", nil),
 				Description: "Hetzner Cloud Token",
 				Sensitive:   true,
 			},
+			"token_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				ConflictsWith: []string{"token"},
+				Description: "Path to a file containing the Hetzner Cloud Token",
+			},
+			"endpoint": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc("HCLOUD_ENDPOINT", hcloud.Endpoint),
+				Description: "Hetzner Cloud API endpoint",
+			},
+			"max_retries": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     5,
+				Description: "How often a rate limited, locked or failed request is retried",
+			},
+			"max_retry_wait": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     60,
+				Description: "Maximum time in seconds to wait before retrying a request",
+			},
+			"poll_interval": {
+				Type:        schema.TypeInt,
+				Optional:    true,
+				Default:     500,
+				Description: "Interval in milliseconds to poll running actions",
+			},
+			"default_labels": {
+				Type:         schema.TypeMap,
+				Elem:         &schema.Schema{Type: schema.TypeString},
+				Optional:     true,
+				ValidateFunc: validateLabels,
+				Description:  "Labels added to every resource, which supports labels",
+			},
 		},
 		DataSourcesMap: map[string]*schema.Resource {
 			"hcloud_datacenter": dataSourceHcloudDatacenter(),
 			"hcloud_image": dataSourceHcloudImage(),
 			"hcloud_servertype": dataSourceHcloudServertype(),
 			"hcloud_location": dataSourceHcloudLocation(),
+			"hcloud_iso": dataSourceHcloudISO(),
+			"hcloud_images": dataSourceHcloudImages(),
 		},
 		ResourcesMap: map[string]*schema.Resource{
 			"hcloud_server" : resourceHcloudServer(),
 			"hcloud_sshkey" : resourceHcloudSSHKey(),
 			"hcloud_rescue" : resourceHcloudRescue(),
+			"hcloud_floating_ip" : resourceHcloudFloatingIP(),
+			"hcloud_floating_ip_assignment" : resourceHcloudFloatingIPAssignment(),
+			"hcloud_rdns" : resourceHcloudRDNS(),
+			"hcloud_snapshot" : resourceHcloudSnapshot(),
 		},
 		ConfigureFunc: providerConfigure,
 	}
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	client := hcloud.NewClient(hcloud.WithToken(d.Get("token").(string)))
-	return client, nil
-}
\ No newline at end of file
+	token := d.Get("token").(string)
+
+	// a token file is only set explicitly, so it wins over HCLOUD_TOKEN
+	if path, ok := d.GetOk("token_file"); ok {
+		path, err := homedir.Expand(path.(string))
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("Error reading token_file: %s", err)
+		}
+		token = strings.TrimSpace(string(content))
+	}
+
+	if len(token) < 1 {
+		return nil, errors.New("No Hetzner Cloud Token configured, set token, token_file or HCLOUD_TOKEN")
+	}
+
+	// retry rejected requests instead of failing in the middle of an apply, the transport never
+	// passes a rate limited response on, so hcloud-go doesn't retry them again
+	transport := newRetryTransport(d.Get("max_retries").(int), time.Duration(d.Get("max_retry_wait").(int)) * time.Second)
+
+	pollInterval := time.Duration(d.Get("poll_interval").(int)) * time.Millisecond
+	if pollInterval <= 0 {
+		return nil, errors.New("poll_interval has to be greater than 0")
+	}
+
+	config := &Config{
+		Client: hcloud.NewClient(
+			hcloud.WithToken(token),
+			hcloud.WithEndpoint(d.Get("endpoint").(string)),
+			hcloud.WithHTTPClient(&http.Client{Transport: transport}),
+			hcloud.WithPollInterval(pollInterval),
+		),
+		PollInterval: pollInterval,
+		DefaultLabels: expandLabels(d.Get("default_labels").(map[string]interface{})),
+	}
+
+	// fail early instead of in the middle of an apply
+	err := config.verifyToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
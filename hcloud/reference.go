@@ -0,0 +1,147 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// references are ids or names of objects, the objects are looked up with the matching get
+// function. The name of an object is recorded in state next to its id, so referencing
+// the same object by its id or name doesn't cause a diff.
+
+// referenceFor returns the name of an object, or its id, if the object has no name
+func referenceFor(id int, name string) string {
+	if len(name) > 0 {
+		return name
+	}
+	return strconv.Itoa(id)
+}
+
+// suppressEquivalentReference suppresses the diff, if the new value references the object,
+// whose id is recorded in idKey
+func suppressEquivalentReference(idKey string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		if len(old) < 1 {
+			return false
+		}
+		return old == new || new == d.Get(idKey).(string)
+	}
+}
+
+// suppressEquivalentReferenceList works like suppressEquivalentReference for the elements
+// of a list, the ids are recorded in the list idKey
+func suppressEquivalentReferenceList(idKey string) schema.SchemaDiffSuppressFunc {
+	return func(k, old, new string, d *schema.ResourceData) bool {
+		if len(old) < 1 || strings.HasSuffix(k, ".#") {
+			return old == new
+		}
+		i := k[strings.LastIndex(k, ".")+1:]
+		return old == new || new == d.Get(idKey + "." + i).(string)
+	}
+}
+
+// referenceChanged reports whether the reference in key points to another object than the
+// id recorded in key + "_id". DiffSuppressFunc doesn't apply to HasChange of a ResourceDiff,
+// so switching between the name and the id of the same object looks like a change.
+func referenceChanged(d *schema.ResourceDiff, key string) bool {
+	if !d.HasChange(key) {
+		return false
+	}
+	if !d.NewValueKnown(key) {
+		return true
+	}
+
+	oldID, _ := d.GetChange(key + "_id")
+	return len(oldID.(string)) < 1 || d.Get(key).(string) != oldID.(string)
+}
+
+// referenceListChanged works like referenceChanged for the list key, the ids are recorded in
+// the list key + "_ids"
+func referenceListChanged(d *schema.ResourceDiff, key string, idsKey string) bool {
+	if !d.HasChange(key) {
+		return false
+	}
+	if !d.NewValueKnown(key) {
+		return true
+	}
+
+	oldIDs, _ := d.GetChange(idsKey)
+	refs := d.Get(key).([]interface{})
+	if len(refs) != len(oldIDs.([]interface{})) {
+		return true
+	}
+
+	old, _ := d.GetChange(key)
+	for i, ref := range refs {
+		if ref != old.([]interface{})[i] && ref != oldIDs.([]interface{})[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func getServerType(ctx context.Context, client *hcloud.Client, ref string) (*hcloud.ServerType, error) {
+	st, _, err := client.ServerType.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if st == nil {
+		return nil, fmt.Errorf("Server Type %s not found", ref)
+	}
+	return st, nil
+}
+
+func getImage(ctx context.Context, client *hcloud.Client, ref string) (*hcloud.Image, error) {
+	img, _, err := client.Image.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if img == nil {
+		return nil, fmt.Errorf("Image %s not found", ref)
+	}
+	return img, nil
+}
+
+func getLocation(ctx context.Context, client *hcloud.Client, ref string) (*hcloud.Location, error) {
+	loc, _, err := client.Location.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if loc == nil {
+		return nil, fmt.Errorf("Location %s not found", ref)
+	}
+	return loc, nil
+}
+
+func getDatacenter(ctx context.Context, client *hcloud.Client, ref string) (*hcloud.Datacenter, error) {
+	dc, _, err := client.Datacenter.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if dc == nil {
+		return nil, fmt.Errorf("Datacenter %s not found", ref)
+	}
+	return dc, nil
+}
+
+// getSSHKey looks up a ssh key by its id, name or fingerprint
+func getSSHKey(ctx context.Context, client *hcloud.Client, ref string) (*hcloud.SSHKey, error) {
+	key, _, err := client.SSHKey.Get(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if key == nil && strings.Contains(ref, ":") {
+		key, _, err = client.SSHKey.GetByFingerprint(ctx, ref)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("SSH Key %s not found", ref)
+	}
+	return key, nil
+}
@@ -0,0 +1,237 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/validation"
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"context"
+	"strconv"
+	"errors"
+)
+
+func resourceHcloudFloatingIP() *schema.Resource {
+	r := &schema.Resource{
+		Create: resourceHcloudFloatingIPCreate,
+		Read:   resourceHcloudFloatingIPRead,
+		Update: resourceHcloudFloatingIPUpdate,
+		Delete: resourceHcloudFloatingIPDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceHcloudFloatingIPImport,
+		},
+
+		CustomizeDiff: readOnlyCustomizeDiff,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"type": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					string(hcloud.FloatingIPTypeIPv4),
+					string(hcloud.FloatingIPTypeIPv6),
+				}, false),
+			},
+			"home_location": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"dns_ptr": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ip_address": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"ip_network": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+		},
+	}
+
+	// the home location id was a number in version 0
+	r.StateUpgraders = idStateUpgraders(r, "home_location")
+
+	return r
+}
+
+func resourceHcloudFloatingIPImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	err := resourceHcloudFloatingIPRead(d, meta)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceHcloudFloatingIPCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	// get home location object
+	lid, err := parseID(d.Get("home_location").(string))
+	if err != nil {
+		return err
+	}
+
+	loc, _, err := m.(*Config).Client.Location.GetByID(ctx, lid)
+	if err != nil {
+		return err
+	}
+	if loc == nil {
+		return errors.New("Location not found")
+	}
+
+	// create floating ip
+	description := d.Get("description").(string)
+	res, _, err := m.(*Config).Client.FloatingIP.Create(ctx, hcloud.FloatingIPCreateOpts{
+		Type: hcloud.FloatingIPType(d.Get("type").(string)),
+		HomeLocation: loc,
+		Description: &description,
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId(strconv.Itoa(res.FloatingIP.ID))
+
+	// wait for create action, if there is one, and check for errors
+	if res.Action != nil {
+		err = m.(*Config).waitForAction(ctx, res.Action)
+		if err != nil {
+			return err
+		}
+	}
+
+	// check if DNS PTR is set
+	if ptr, ok := d.GetOk("dns_ptr"); ok {
+		sptr := ptr.(string)
+
+		err = changeFloatingIPDNSPtr(ctx, m.(*Config), res.FloatingIP, res.FloatingIP.IP.String(), &sptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceHcloudFloatingIPRead(d, m)
+}
+
+func resourceHcloudFloatingIPRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	// convert id from string to int
+	id, err := parseID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// get floating ip object
+	fip, _, err := m.(*Config).Client.FloatingIP.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if fip != nil {
+		// update resource data
+		d.Set("type", string(fip.Type))
+		d.Set("home_location", strconv.Itoa(fip.HomeLocation.ID))
+		d.Set("description", fip.Description)
+		d.Set("dns_ptr", fip.DNSPtrForIP(fip.IP))
+		d.Set("ip_address", fip.IP.String())
+
+		if fip.Network != nil {
+			d.Set("ip_network", fip.Network.String())
+		} else {
+			d.Set("ip_network", "")
+		}
+	} else {
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceHcloudFloatingIPUpdate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	// convert id from string to int
+	id, err := parseID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// get floating ip object
+	fip, _, err := m.(*Config).Client.FloatingIP.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if fip != nil {
+		// update description, when necessary
+		if fip.Description != d.Get("description").(string) {
+			fip, _, err = m.(*Config).Client.FloatingIP.Update(ctx, fip, hcloud.FloatingIPUpdateOpts{
+				Description: d.Get("description").(string),
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		// update DNS PTR, when necessary
+		if fip.DNSPtrForIP(fip.IP) != d.Get("dns_ptr").(string) {
+			var ptr *string
+			if sptr := d.Get("dns_ptr").(string); len(sptr) > 0 {
+				ptr = &sptr
+			}
+
+			err = changeFloatingIPDNSPtr(ctx, m.(*Config), fip, fip.IP.String(), ptr)
+			if err != nil {
+				return err
+			}
+		}
+	} else {
+		d.SetId("")
+		return nil
+	}
+
+	return resourceHcloudFloatingIPRead(d, m)
+}
+
+func resourceHcloudFloatingIPDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	// convert id from string to int
+	id, err := parseID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// get floating ip object
+	fip, _, err := m.(*Config).Client.FloatingIP.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// check that floating ip still exists
+	if fip == nil {
+		d.SetId("")
+		return nil
+	}
+
+	// send floating ip delete request
+	_, err = m.(*Config).Client.FloatingIP.Delete(ctx, fip)
+	return err
+}
@@ -0,0 +1,166 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"context"
+	"strconv"
+	"errors"
+)
+
+func resourceHcloudFloatingIPAssignment() *schema.Resource {
+	r := &schema.Resource{
+		Create: resourceHcloudFloatingIPAssignmentCreate,
+		Read:   resourceHcloudFloatingIPAssignmentRead,
+		Delete: resourceHcloudFloatingIPAssignmentDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceHcloudFloatingIPAssignmentImport,
+		},
+
+		CustomizeDiff: readOnlyCustomizeDiff,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"floating_ip": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"server": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+		},
+	}
+
+	// the floating ip and server ids were numbers in version 0
+	r.StateUpgraders = idStateUpgraders(r, "floating_ip", "server")
+
+	return r
+}
+
+func resourceHcloudFloatingIPAssignmentImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	err := resourceHcloudFloatingIPAssignmentRead(d, meta)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceHcloudFloatingIPAssignmentCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	fid, err := parseID(d.Get("floating_ip").(string))
+	if err != nil {
+		return err
+	}
+
+	// get floating ip object
+	fip, _, err := m.(*Config).Client.FloatingIP.GetByID(ctx, fid)
+	if err != nil {
+		return err
+	}
+	if fip == nil {
+		return errors.New("Floating IP not found")
+	}
+
+	sid, err := parseID(d.Get("server").(string))
+	if err != nil {
+		return err
+	}
+
+	// get server object
+	server, _, err := m.(*Config).Client.Server.GetByID(ctx, sid)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return errors.New("Server not found")
+	}
+
+	// send assign request
+	act, _, err := m.(*Config).Client.FloatingIP.Assign(ctx, fip, server)
+	if err != nil {
+		return err
+	}
+
+	// wait for assign action and check for errors
+	err = m.(*Config).waitForAction(ctx, act)
+	if err != nil {
+		return err
+	}
+
+	// a floating ip can only be assigned to one server, so its id identifies the assignment
+	d.SetId(strconv.Itoa(fip.ID))
+
+	return resourceHcloudFloatingIPAssignmentRead(d, m)
+}
+
+func resourceHcloudFloatingIPAssignmentRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	// convert id from string to int
+	id, err := parseID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// get floating ip object
+	fip, _, err := m.(*Config).Client.FloatingIP.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if fip != nil && fip.Server != nil {
+		// update resource data
+		d.Set("floating_ip", strconv.Itoa(fip.ID))
+		d.Set("server", strconv.Itoa(fip.Server.ID))
+	} else {
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceHcloudFloatingIPAssignmentDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	// convert id from string to int
+	id, err := parseID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// get floating ip object
+	fip, _, err := m.(*Config).Client.FloatingIP.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	// check that floating ip still exists and is assigned
+	if fip == nil || fip.Server == nil {
+		d.SetId("")
+		return nil
+	}
+
+	// send unassign request
+	act, _, err := m.(*Config).Client.FloatingIP.Unassign(ctx, fip)
+	if err != nil {
+		return err
+	}
+
+	// wait for unassign action and check for errors
+	err = m.(*Config).waitForAction(ctx, act)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
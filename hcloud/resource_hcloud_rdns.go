@@ -0,0 +1,284 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"context"
+	"strconv"
+	"strings"
+	"errors"
+	"fmt"
+	"net"
+)
+
+const (
+	rdnsTypeServer     = "server"
+	rdnsTypeFloatingIP = "floating_ip"
+)
+
+func resourceHcloudRDNS() *schema.Resource {
+	r := &schema.Resource{
+		Create: resourceHcloudRDNSCreate,
+		Read:   resourceHcloudRDNSRead,
+		Update: resourceHcloudRDNSUpdate,
+		Delete: resourceHcloudRDNSDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceHcloudRDNSImport,
+		},
+
+		CustomizeDiff: readOnlyCustomizeDiff,
+
+		SchemaVersion: 1,
+
+		Schema: map[string]*schema.Schema{
+			"server": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				ConflictsWith: []string{"floating_ip"},
+			},
+			"floating_ip": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+				ForceNew: true,
+				ConflictsWith: []string{"server"},
+			},
+			"ip_address": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+				DiffSuppressFunc: suppressEquivalentIP,
+			},
+			"dns_ptr": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+			},
+		},
+	}
+
+	// the server and floating ip ids were numbers in version 0
+	r.StateUpgraders = idStateUpgraders(r, "server", "floating_ip")
+
+	return r
+}
+
+// suppressEquivalentIP ignores different notations of the same address, the state holds
+// the canonical form
+func suppressEquivalentIP(k, old, new string, d *schema.ResourceData) bool {
+	oldIP := net.ParseIP(old)
+	return oldIP != nil && oldIP.Equal(net.ParseIP(new))
+}
+
+func resourceHcloudRDNSImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	// id has the form <type>-<id>-<ip>
+	parts := strings.SplitN(d.Id(), "-", 3)
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid id %q, expected <type>-<id>-<ip>", d.Id())
+	}
+
+	id, err := parseID(parts[1])
+	if err != nil {
+		return nil, err
+	}
+
+	switch parts[0] {
+	case rdnsTypeServer:
+		d.Set("server", strconv.Itoa(id))
+	case rdnsTypeFloatingIP:
+		d.Set("floating_ip", strconv.Itoa(id))
+	default:
+		return nil, fmt.Errorf("invalid type %q, expected %s or %s", parts[0], rdnsTypeServer, rdnsTypeFloatingIP)
+	}
+	d.Set("ip_address", parts[2])
+
+	err = resourceHcloudRDNSRead(d, meta)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceHcloudRDNSCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	ip := net.ParseIP(d.Get("ip_address").(string))
+	if ip == nil {
+		return errors.New("ip_address is not a valid IP address")
+	}
+
+	ptr := d.Get("dns_ptr").(string)
+	err := resourceHcloudRDNSSet(ctx, d, m.(*Config), ip.String(), &ptr)
+	if err != nil {
+		return err
+	}
+
+	if sid, ok := d.GetOk("server"); ok {
+		d.SetId(fmt.Sprintf("%s-%s-%s", rdnsTypeServer, sid.(string), ip.String()))
+	} else {
+		d.SetId(fmt.Sprintf("%s-%s-%s", rdnsTypeFloatingIP, d.Get("floating_ip").(string), ip.String()))
+	}
+
+	return resourceHcloudRDNSRead(d, m)
+}
+
+func resourceHcloudRDNSRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	ip := net.ParseIP(d.Get("ip_address").(string))
+	if ip == nil {
+		return errors.New("ip_address is not a valid IP address")
+	}
+
+	var ptr string
+	if sid, ok := d.GetOk("server"); ok {
+		id, err := parseID(sid.(string))
+		if err != nil {
+			return err
+		}
+
+		// get server object
+		server, _, err := m.(*Config).Client.Server.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if server == nil {
+			d.SetId("")
+			return nil
+		}
+
+		if server.PublicNet.IPv4.IP.Equal(ip) {
+			ptr = server.PublicNet.IPv4.DNSPtr
+		} else {
+			ptr = server.PublicNet.IPv6.DNSPtr[ip.String()]
+		}
+	} else {
+		id, err := parseID(d.Get("floating_ip").(string))
+		if err != nil {
+			return err
+		}
+
+		// get floating ip object
+		fip, _, err := m.(*Config).Client.FloatingIP.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+
+		if fip == nil {
+			d.SetId("")
+			return nil
+		}
+
+		ptr = fip.DNSPtrForIP(ip)
+	}
+
+	// without a dns ptr there is nothing left to manage
+	if len(ptr) < 1 {
+		d.SetId("")
+		return nil
+	}
+
+	d.Set("ip_address", ip.String())
+	d.Set("dns_ptr", ptr)
+
+	return nil
+}
+
+func resourceHcloudRDNSUpdate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	if d.HasChange("dns_ptr") {
+		ptr := d.Get("dns_ptr").(string)
+		err := resourceHcloudRDNSSet(ctx, d, m.(*Config), d.Get("ip_address").(string), &ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceHcloudRDNSRead(d, m)
+}
+
+func resourceHcloudRDNSDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	// reset dns ptr to the default
+	err := resourceHcloudRDNSSet(ctx, d, m.(*Config), d.Get("ip_address").(string), nil)
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceHcloudRDNSSet(ctx context.Context, d *schema.ResourceData, config *Config, ip string, ptr *string) error {
+	if sid, ok := d.GetOk("server"); ok {
+		id, err := parseID(sid.(string))
+		if err != nil {
+			return err
+		}
+
+		// get server object
+		server, _, err := config.Client.Server.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if server == nil {
+			return errors.New("Server not found")
+		}
+
+		return changeServerDNSPtr(ctx, config, server, ip, ptr)
+	}
+
+	if fid, ok := d.GetOk("floating_ip"); ok {
+		id, err := parseID(fid.(string))
+		if err != nil {
+			return err
+		}
+
+		// get floating ip object
+		fip, _, err := config.Client.FloatingIP.GetByID(ctx, id)
+		if err != nil {
+			return err
+		}
+		if fip == nil {
+			return errors.New("Floating IP not found")
+		}
+
+		return changeFloatingIPDNSPtr(ctx, config, fip, ip, ptr)
+	}
+
+	return errors.New("either server or floating_ip has to be set")
+}
+
+// changeServerDNSPtr sets the dns ptr of a server ip and waits for the action to finish.
+// A nil ptr resets the dns ptr to the default.
+func changeServerDNSPtr(ctx context.Context, config *Config, server *hcloud.Server, ip string, ptr *string) error {
+	// send dns ptr change request
+	act, _, err := config.Client.Server.ChangeDNSPtr(ctx, server, ip, ptr)
+	if err != nil {
+		return err
+	}
+
+	// wait for action to finish and check for errors
+	return config.waitForAction(ctx, act)
+}
+
+// changeFloatingIPDNSPtr sets the dns ptr of a floating ip and waits for the action to finish.
+// A nil ptr resets the dns ptr to the default.
+func changeFloatingIPDNSPtr(ctx context.Context, config *Config, fip *hcloud.FloatingIP, ip string, ptr *string) error {
+	// send dns ptr change request
+	act, _, err := config.Client.FloatingIP.ChangeDNSPtr(ctx, fip, ip, ptr)
+	if err != nil {
+		return err
+	}
+
+	// wait for action to finish and check for errors
+	return config.waitForAction(ctx, act)
+}
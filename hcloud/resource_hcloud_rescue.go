@@ -5,20 +5,30 @@ import (
 	"github.com/hetznercloud/hcloud-go/hcloud"
 	"context"
 	"github.com/satori/go.uuid"
-	"strconv"
+	"time"
 	"errors"
 )
 
 func resourceHcloudRescue() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceHcloudRescueCreate,
 		Read:   resourceHcloudRescueRead,
 		Delete: resourceHcloudRescueDelete,
 		Update: resourceHcloudRescueUpdate,
 
+		CustomizeDiff: readOnlyCustomizeDiff,
+
+		SchemaVersion: 1,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(10 * time.Minute),
+			Update: schema.DefaultTimeout(10 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
 		Schema: map[string]*schema.Schema{
 			"server": &schema.Schema{
-				Type:     schema.TypeInt,
+				Type:     schema.TypeString,
 				Required: true,
 				ForceNew: true,
 			},
@@ -31,7 +41,7 @@ func resourceHcloudRescue() *schema.Resource {
 			"ssh_keys": &schema.Schema{
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
-					Type:     schema.TypeInt,
+					Type:     schema.TypeString,
 				},
 				Optional: true,
 				ForceNew: true,
@@ -62,11 +72,24 @@ func resourceHcloudRescue() *schema.Resource {
 			},
 		},
 	}
+
+	// server and ssh key ids were numbers in version 0
+	r.StateUpgraders = idStateUpgraders(r, "server", "ssh_keys")
+
+	return r
 }
 
 func resourceHcloudRescueCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	sid, err := parseID(d.Get("server").(string))
+	if err != nil {
+		return err
+	}
+
 	// get server object
-	server, _, err := m.(*hcloud.Client).Server.GetByID(context.Background(), d.Get("server").(int))
+	server, _, err := m.(*Config).Client.Server.GetByID(ctx, sid)
 	if err != nil {
 		return err
 	}
@@ -75,14 +98,13 @@ func resourceHcloudRescueCreate(d *schema.ResourceData, m interface{}) error {
 		// deactivate rescue first, if it's already enabled
 		if server.RescueEnabled {
 			// disable rescue request
-			act, _, err := m.(*hcloud.Client).Server.DisableRescue(context.Background(), server)
+			act, _, err := m.(*Config).Client.Server.DisableRescue(ctx, server)
 			if err != nil {
 				return err
 			}
 
 			// wait for action and check for error
-			_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-			err = <-errch
+			err = m.(*Config).waitForAction(ctx, act)
 			if err != nil {
 				return err
 			}
@@ -92,13 +114,10 @@ func resourceHcloudRescueCreate(d *schema.ResourceData, m interface{}) error {
 		ids := d.Get("ssh_keys").([]interface{})
 		ssh := make([]*hcloud.SSHKey, len(ids))
 		for i, v := range ids {
-			key, _, err := m.(*hcloud.Client).SSHKey.GetByID(context.Background(), v.(int))
+			key, err := getSSHKey(ctx, m.(*Config).Client, v.(string))
 			if err != nil {
 				return err
 			}
-			if key == nil {
-				return errors.New("SSH Key " + strconv.Itoa(v.(int)) + " not found")
-			}
 			ssh[i] = key
 		}
 
@@ -116,7 +135,7 @@ func resourceHcloudRescueCreate(d *schema.ResourceData, m interface{}) error {
 		}
 
 		// enable rescue request
-		rescue, _, err := m.(*hcloud.Client).Server.EnableRescue(context.Background(), server, hcloud.ServerEnableRescueOpts{
+		rescue, _, err := m.(*Config).Client.Server.EnableRescue(ctx, server, hcloud.ServerEnableRescueOpts{
 			Type:    rt,
 			SSHKeys: ssh,
 		})
@@ -125,8 +144,7 @@ func resourceHcloudRescueCreate(d *schema.ResourceData, m interface{}) error {
 		}
 
 		// wait for action and check for error
-		_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), rescue.Action)
-		err = <-errch
+		err = m.(*Config).waitForAction(ctx, rescue.Action)
 		if err != nil {
 			return err
 		}
@@ -135,27 +153,25 @@ func resourceHcloudRescueCreate(d *schema.ResourceData, m interface{}) error {
 		d.Set("password", rescue.RootPassword)
 
 		if d.Get("reboot_on_activation").(bool) { // soft reboot instead of reset, if reboot is set to true
-			act, _, err := m.(*hcloud.Client).Server.Reboot(context.Background(), server)
+			act, _, err := m.(*Config).Client.Server.Reboot(ctx, server)
 			if err != nil {
 				return err
 			}
 
 			// wait for action and check for error
-			_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-			err = <-errch
+			err = m.(*Config).waitForAction(ctx, act)
 			if err != nil {
 				return err
 			}
 
 		} else if d.Get("reset_on_activation").(bool) { // reset if reboot is false and reset is true
-			act, _, err := m.(*hcloud.Client).Server.Reset(context.Background(), server)
+			act, _, err := m.(*Config).Client.Server.Reset(ctx, server)
 			if err != nil {
 				return err
 			}
 
 			// wait for action and check for error
-			_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-			err = <-errch
+			err = m.(*Config).waitForAction(ctx, act)
 			if err != nil {
 				return err
 			}
@@ -178,8 +194,16 @@ func resourceHcloudRescueRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceHcloudRescueDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	sid, err := parseID(d.Get("server").(string))
+	if err != nil {
+		return err
+	}
+
 	// get server object
-	server, _, err := m.(*hcloud.Client).Server.GetByID(context.Background(), d.Get("server").(int))
+	server, _, err := m.(*Config).Client.Server.GetByID(ctx, sid)
 	if err != nil {
 		return err
 	}
@@ -188,14 +212,13 @@ func resourceHcloudRescueDelete(d *schema.ResourceData, m interface{}) error {
 		// disable rescue if enabled
 		if server.RescueEnabled {
 			// disable rescue request
-			act, _, err := m.(*hcloud.Client).Server.DisableRescue(context.Background(), server)
+			act, _, err := m.(*Config).Client.Server.DisableRescue(ctx, server)
 			if err != nil {
 				return err
 			}
 
 			// wait for action and check for error
-			_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-			err = <-errch
+			err = m.(*Config).waitForAction(ctx, act)
 			if err != nil {
 				return err
 			}
@@ -203,27 +226,25 @@ func resourceHcloudRescueDelete(d *schema.ResourceData, m interface{}) error {
 
 		// check for reboot or reset
 		if d.Get("reboot_on_deactivation").(bool) { // soft reboot instead of reset, if reboot is set to true
-			act, _, err := m.(*hcloud.Client).Server.Reboot(context.Background(), server)
+			act, _, err := m.(*Config).Client.Server.Reboot(ctx, server)
 			if err != nil {
 				return err
 			}
 
 			// wait for action and check for error
-			_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-			err = <-errch
+			err = m.(*Config).waitForAction(ctx, act)
 			if err != nil {
 				return err
 			}
 
 		} else if d.Get("reset_on_deactivation").(bool) { // reset if reboot is false and reset is true
-			act, _, err := m.(*hcloud.Client).Server.Reset(context.Background(), server)
+			act, _, err := m.(*Config).Client.Server.Reset(ctx, server)
 			if err != nil {
 				return err
 			}
 
 			// wait for action and check for error
-			_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-			err = <-errch
+			err = m.(*Config).waitForAction(ctx, act)
 			if err != nil {
 				return err
 			}
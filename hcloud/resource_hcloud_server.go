@@ -2,15 +2,35 @@ package hcloud
 
 import (
 	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hashicorp/terraform/helper/customdiff"
+	"github.com/hashicorp/terraform/helper/validation"
 	"github.com/hetznercloud/hcloud-go/hcloud"
+	hcloudschema "github.com/hetznercloud/hcloud-go/hcloud/schema"
 	"context"
 	"strconv"
 	"time"
 	"errors"
+	"net"
+	"fmt"
+	"log"
+	"bytes"
+	"encoding/json"
+	"strings"
+)
+
+const (
+	serverPowerStateOn          = "on"
+	serverPowerStateOff         = "off"
+	serverPowerStateOffGraceful = "off_graceful"
+)
+
+const (
+	serverDiskDowngradeFail    = "fail"
+	serverDiskDowngradeReplace = "replace"
 )
 
 func resourceHcloudServer() *schema.Resource {
-	return &schema.Resource{
+	r := &schema.Resource{
 		Create: resourceHcloudServerCreate,
 		Read:   resourceHcloudServerRead,
 		Update: resourceHcloudServerUpdate,
@@ -20,44 +40,111 @@ func resourceHcloudServer() *schema.Resource {
 			State: resourceHcloudServerImport,
 		},
 
-		/*
-		 *	@TODO: implement ISO attach and detach
-		 */
+		SchemaVersion: 1,
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(30 * time.Minute),
+			Delete: schema.DefaultTimeout(10 * time.Minute),
+		},
+
+		CustomizeDiff: customdiff.All(
+			readOnlyCustomizeDiff,
+			labelsCustomizeDiff,
+			resourceHcloudServerReferencesCustomizeDiff,
+			resourceHcloudServerCustomizeDiff,
+			resourceHcloudServerDiskCustomizeDiff,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
 				Required: true,
 			},
 			"server_type": &schema.Schema{
-				Type:     schema.TypeInt,
+				Type:     schema.TypeString,
 				Required: true,
+				DiffSuppressFunc: suppressEquivalentReference("server_type_id"),
+			},
+			"server_type_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 			"datacenter": &schema.Schema{
-				Type:     schema.TypeInt,
+				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
 				ForceNew: true,
 				ConflictsWith: []string{"location"},
+				DiffSuppressFunc: suppressEquivalentReference("datacenter_id"),
+			},
+			"datacenter_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 			"location": &schema.Schema{
-				Type:     schema.TypeInt,
+				Type:     schema.TypeString,
 				Optional: true,
 				Computed: true,
 				ForceNew: true,
 				ConflictsWith: []string{"datacenter"},
+				DiffSuppressFunc: suppressEquivalentReference("location_id"),
+			},
+			"location_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
 			},
 			"image": &schema.Schema{
-				Type:     schema.TypeInt,
+				Type:     schema.TypeString,
 				Required: true,
-				ForceNew: true,
+				DiffSuppressFunc: suppressEquivalentReference("image_id"),
+			},
+			"image_id": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"rebuild_on_image_change": &schema.Schema{
+				Type: schema.TypeBool,
+				Optional: true,
+				Default: false,
+			},
+			"rebuild_triggers": &schema.Schema{
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+				},
+				Optional: true,
 			},
 			"ssh_keys": &schema.Schema{
 				Type: schema.TypeList,
 				Elem: &schema.Schema{
-					Type:     schema.TypeInt,
+					Type:     schema.TypeString,
 				},
 				Optional: true,
 				ForceNew: true,
+				DiffSuppressFunc: suppressEquivalentReferenceList("ssh_key_ids"),
+			},
+			"ssh_key_ids": &schema.Schema{
+				Type: schema.TypeList,
+				Elem: &schema.Schema{
+					Type:     schema.TypeString,
+				},
+				Computed: true,
+			},
+			"labels": &schema.Schema{
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				ValidateFunc: validateLabels,
+			},
+			"default_labels": &schema.Schema{
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
 			},
 			"user_data": &schema.Schema{
 				Type:     schema.TypeString,
@@ -85,13 +172,18 @@ func resourceHcloudServer() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"ipv6_network": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 			"ipv6_ptr": &schema.Schema{
 				Type: schema.TypeMap,
 				Elem: &schema.Schema{
 					Type:     schema.TypeString,
 				},
+				Optional: true,
 				Computed: true,
-				//@TODO: implement setting ipv6_ptr
+				ValidateFunc: validateIPv6PtrMap,
 			},
 			"root_pw": &schema.Schema{
 				Type: schema.TypeString,
@@ -103,6 +195,19 @@ func resourceHcloudServer() *schema.Resource {
 				Optional: true,
 				Default: true,
 			},
+			"disk_size": &schema.Schema{
+				Type: schema.TypeInt,
+				Computed: true,
+			},
+			"disk_downgrade": &schema.Schema{
+				Type: schema.TypeString,
+				Optional: true,
+				Default: serverDiskDowngradeFail,
+				ValidateFunc: validation.StringInSlice([]string{
+					serverDiskDowngradeFail,
+					serverDiskDowngradeReplace,
+				}, false),
+			},
 			"backup": &schema.Schema{
 				Type: schema.TypeBool,
 				Optional: true,
@@ -113,8 +218,69 @@ func resourceHcloudServer() *schema.Resource {
 				Optional: true,
 				Default: "",
 			},
+			"iso": &schema.Schema{
+				Type: schema.TypeString,
+				Optional: true,
+			},
+			"delete_protection": &schema.Schema{
+				Type: schema.TypeBool,
+				Optional: true,
+				Default: false,
+			},
+			"rebuild_protection": &schema.Schema{
+				Type: schema.TypeBool,
+				Optional: true,
+				Default: false,
+			},
+			"power_state": &schema.Schema{
+				Type: schema.TypeString,
+				Optional: true,
+				Computed: true,
+				ValidateFunc: validation.StringInSlice([]string{
+					serverPowerStateOn,
+					serverPowerStateOff,
+					serverPowerStateOffGraceful,
+				}, false),
+			},
+			"resize_shutdown": serverShutdownPolicySchema(),
+			"shutdown_before_deletion": &schema.Schema{
+				Type: schema.TypeBool,
+				Optional: true,
+				Default: false,
+			},
+			"snapshot_on_destroy": &schema.Schema{
+				Type: schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"description": &schema.Schema{
+							Type: schema.TypeString,
+							Optional: true,
+							Default: "final snapshot of {{name}} ({{id}}) at {{timestamp}}",
+						},
+						"labels": &schema.Schema{
+							Type: schema.TypeMap,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Optional: true,
+							ValidateFunc: validateLabels,
+						},
+						"manifest": &schema.Schema{
+							Type: schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
 		},
 	}
+
+	// the references and their ids were numbers in version 0
+	r.StateUpgraders = idStateUpgraders(r, "server_type", "server_type_id", "datacenter", "datacenter_id", "location", "location_id", "image", "image_id", "ssh_keys", "ssh_key_ids", "iso")
+
+	return r
 }
 
 func resourceHcloudServerImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
@@ -128,64 +294,54 @@ func resourceHcloudServerImport(d *schema.ResourceData, meta interface{}) ([]*sc
 }
 
 func resourceHcloudServerCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
 	// get server type object
-	st, _, err := m.(*hcloud.Client).ServerType.GetByID(context.Background(), d.Get("server_type").(int))
+	st, err := getServerType(ctx, m.(*Config).Client, d.Get("server_type").(string))
 	if err != nil {
 		return err
 	}
-	if st == nil {
-		return errors.New("Server Type not found")
-	}
 
 	// get image object
-	img, _, err := m.(*hcloud.Client).Image.GetByID(context.Background(), d.Get("image").(int))
+	img, err := getImage(ctx, m.(*Config).Client, d.Get("image").(string))
 	if err != nil {
 		return err
 	}
-	if img == nil {
-		return errors.New("Image not found")
-	}
 
 	// check if location is set and get location object
 	var loc *hcloud.Location = nil
-	if lid, ok := d.GetOk("location"); ok {
-		loc, _, err = m.(*hcloud.Client).Location.GetByID(context.Background(), lid.(int))
+	if ref, ok := d.GetOk("location"); ok {
+		loc, err = getLocation(ctx, m.(*Config).Client, ref.(string))
 		if err != nil {
 			return err
 		}
-		if loc == nil {
-			return errors.New("Location not found")
-		}
 	}
 
 	// check if datacenter is set and get datacenter object
 	var dc *hcloud.Datacenter = nil
-	if dcid, ok := d.GetOk("datacenter"); ok {
-		dc, _, err = m.(*hcloud.Client).Datacenter.GetByID(context.Background(), dcid.(int))
+	if ref, ok := d.GetOk("datacenter"); ok {
+		dc, err = getDatacenter(ctx, m.(*Config).Client, ref.(string))
 		if err != nil {
 			return err
 		}
-		if dc == nil {
-			return errors.New("Datacenter not found")
-		}
 	}
 
-	// transform ssh key ids into array of SSHKey objects
-	ids := d.Get("ssh_keys").([]interface{})
-	ssh := make([]*hcloud.SSHKey, len(ids))
-	for i, v := range ids {
-		key, _ , err := m.(*hcloud.Client).SSHKey.GetByID(context.Background(), v.(int))
+	// transform ssh key ids, names and fingerprints into array of SSHKey objects
+	refs := d.Get("ssh_keys").([]interface{})
+	ssh := make([]*hcloud.SSHKey, len(refs))
+	sshids := make([]string, len(refs))
+	for i, v := range refs {
+		key, err := getSSHKey(ctx, m.(*Config).Client, v.(string))
 		if err != nil {
 			return err
 		}
-		if key == nil {
-			return errors.New("SSH Key " + strconv.Itoa(v.(int)) + " not found")
-		}
 		ssh[i] = key
+		sshids[i] = strconv.Itoa(key.ID)
 	}
 
 	// create server
-	server, _, err := m.(*hcloud.Client).Server.Create(context.Background(), hcloud.ServerCreateOpts{
+	server, _, err := m.(*Config).Client.Server.Create(ctx, hcloud.ServerCreateOpts{
 		Name: d.Get("name").(string),
 		ServerType: st,
 		Image: img,
@@ -193,71 +349,119 @@ func resourceHcloudServerCreate(d *schema.ResourceData, m interface{}) error {
 		Location: loc,
 		Datacenter: dc,
 		UserData: d.Get("user_data").(string),
+		Labels: m.(*Config).mergeLabels(d.Get("labels").(map[string]interface{})),
+		StartAfterCreate: hcloud.Bool(d.Get("power_state").(string) != serverPowerStateOff && d.Get("power_state").(string) != serverPowerStateOffGraceful),
 	})
-
-	// wait for server_create action and check for errors
-	_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), server.Action)
-	err = <-errch
 	if err != nil {
 		return err
 	}
 
-	// set server id and save root password
+	// the server exists from now on, so set server id and save root password right away
 	d.SetId(strconv.Itoa(server.Server.ID))
 	d.Set("root_pw", server.RootPassword)
+	d.Set("disk_size", st.Disk)
+
+	// record names and ids of the referenced objects
+	d.Set("server_type", st.Name)
+	d.Set("server_type_id", strconv.Itoa(st.ID))
+	d.Set("image", referenceFor(img.ID, img.Name))
+	d.Set("image_id", strconv.Itoa(img.ID))
+	d.Set("datacenter", server.Server.Datacenter.Name)
+	d.Set("datacenter_id", strconv.Itoa(server.Server.Datacenter.ID))
+	d.Set("location", server.Server.Datacenter.Location.Name)
+	d.Set("location_id", strconv.Itoa(server.Server.Datacenter.Location.ID))
+	d.Set("ssh_key_ids", sshids)
+
+	// wait for server_create and the following actions (e.g. start_server) and check for errors
+	err = m.(*Config).waitForActions(ctx, append([]*hcloud.Action{server.Action}, server.NextActions...)...)
+	if err != nil {
+		return fmt.Errorf("Error creating server %d: %s", server.Server.ID, err)
+	}
+
+	// the server is usable now, a failed step doesn't taint it. Read records the state of
+	// the server, so the next plan shows the unfinished steps and the next apply finishes them.
+	err = resourceHcloudServerFinishCreate(ctx, d, m.(*Config), server.Server)
+	if err != nil {
+		log.Printf("[WARN] %s, the next apply finishes the remaining steps", err)
+	}
+
+	// read server data
+	return resourceHcloudServerRead(d, m)
+}
+
+// resourceHcloudServerFinishCreate sets the attributes of a new server, which can't be set
+// on create. It stops at the first failed step.
+func resourceHcloudServerFinishCreate(ctx context.Context, d *schema.ResourceData, config *Config, server *hcloud.Server) error {
+	var err error
 
 	// check if IPv4 PTR is set
 	if ptr, ok := d.GetOk("ipv4_ptr"); ok {
 		sptr := ptr.(string)
 
-		// send change dns ptr request
-		act, _, err := m.(*hcloud.Client).Server.ChangeDNSPtr(context.Background(), server.Server, server.Server.PublicNet.IPv4.IP.String(), &sptr)
+		err = changeServerDNSPtr(ctx, config, server, server.PublicNet.IPv4.IP.String(), &sptr)
 		if err != nil {
-			return nil
+			return fmt.Errorf("Error setting ipv4_ptr of server %d: %s", server.ID, err)
 		}
+	}
 
-		// wait for action to finish and check for errors
-		_, errch = m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-		err = <-errch
+	// check if IPv6 PTRs are set
+	if ptrs, ok := d.GetOk("ipv6_ptr"); ok {
+		err = resourceHcloudServerChangeIPv6Ptr(ctx, config, server, ptrs.(map[string]interface{}))
 		if err != nil {
-			return err
+			return fmt.Errorf("Error setting ipv6_ptr of server %d: %s", server.ID, err)
 		}
 	}
 
-	// send enable or disable backup request
-	var act *hcloud.Action
+	// send enable backup request, new servers don't have backups enabled
 	if backup := d.Get("backup").(bool); backup {
-		act, _, err = m.(*hcloud.Client).Server.EnableBackup(context.Background(), server.Server, d.Get("backup_window").(string))
+		act, _, err := config.Client.Server.EnableBackup(ctx, server, d.Get("backup_window").(string))
 		if err != nil {
-			return err
+			return fmt.Errorf("Error enabling backup of server %d: %s", server.ID, err)
 		}
-	} else {
-		act, _, err = m.(*hcloud.Client).Server.DisableBackup(context.Background(), server.Server)
+
+		// wait for backup action and check for error
+		err = config.waitForAction(ctx, act)
+		if err != nil {
+			return fmt.Errorf("Error enabling backup of server %d: %s", server.ID, err)
+		}
+	}
+
+	// attach iso, if set
+	if cid, ok := d.GetOk("iso"); ok {
+		isoid, err := parseID(cid.(string))
 		if err != nil {
 			return err
 		}
+
+		err = resourceHcloudServerAttachISO(ctx, config, server, isoid)
+		if err != nil {
+			return fmt.Errorf("Error attaching iso to server %d: %s", server.ID, err)
+		}
 	}
 
-	// wait for backup action and check for error
-	_, errch = m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-	err = <-errch
-	if err != nil {
-		return err
+	// enable protection, if set
+	if d.Get("delete_protection").(bool) || d.Get("rebuild_protection").(bool) {
+		err = resourceHcloudServerSetProtection(ctx, config, server, d.Get("delete_protection").(bool), d.Get("rebuild_protection").(bool))
+		if err != nil {
+			return fmt.Errorf("Error enabling protection of server %d: %s", server.ID, err)
+		}
 	}
 
-	// read server data
-	return resourceHcloudServerRead(d, m)
+	return nil
 }
 
 func resourceHcloudServerRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
 	// convert id from string to int
-	id, err := strconv.Atoi(d.Id())
+	id, err := parseID(d.Id())
 	if err != nil {
 		return err
 	}
 
 	// get server object
-	server, _, err := m.(*hcloud.Client).Server.GetByID(context.Background(), id)
+	server, _, err := m.(*Config).Client.Server.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -265,18 +469,55 @@ func resourceHcloudServerRead(d *schema.ResourceData, m interface{}) error {
 	if server != nil {
 		// update resource data
 		d.Set("name", server.Name)
-		d.Set("server_type", server.ServerType.ID)
-		d.Set("datacenter", server.Datacenter.ID)
-		d.Set("location", server.Datacenter.Location.ID)
-		d.Set("image", server.Image.ID)
+		labels, defaults := m.(*Config).splitLabels(server.Labels, d.Get("labels").(map[string]interface{}))
+		d.Set("labels", labels)
+		d.Set("default_labels", defaults)
+		d.Set("server_type", server.ServerType.Name)
+		d.Set("server_type_id", strconv.Itoa(server.ServerType.ID))
+
+		// the disk size is only known, if it was recorded when the server was created or resized
+		if d.Get("disk_size").(int) == 0 {
+			d.Set("disk_size", server.ServerType.Disk)
+		}
+		d.Set("datacenter", server.Datacenter.Name)
+		d.Set("datacenter_id", strconv.Itoa(server.Datacenter.ID))
+		d.Set("location", server.Datacenter.Location.Name)
+		d.Set("location_id", strconv.Itoa(server.Datacenter.Location.ID))
+
+		// the image is gone, if a snapshot the server was created from was deleted
+		if server.Image != nil {
+			d.Set("image", referenceFor(server.Image.ID, server.Image.Name))
+			d.Set("image_id", strconv.Itoa(server.Image.ID))
+		}
 		d.Set("status", server.Status)
+
+		// report drift from the configured power state, servers in transition are left alone
+		switch server.Status {
+		case hcloud.ServerStatusRunning:
+			d.Set("power_state", serverPowerStateOn)
+		case hcloud.ServerStatusOff:
+			if d.Get("power_state").(string) != serverPowerStateOffGraceful {
+				d.Set("power_state", serverPowerStateOff)
+			}
+		}
 		d.Set("created", server.Created.String())
 		d.Set("ipv4", server.PublicNet.IPv4.IP.String())
 		d.Set("ipv4_ptr", server.PublicNet.IPv4.DNSPtr)
 		d.Set("ipv6", server.PublicNet.IPv6.IP.String())
+		d.Set("ipv6_network", server.PublicNet.IPv6.Network.String())
 		d.Set("ipv6_ptr", server.PublicNet.IPv6.DNSPtr)
 		d.Set("backup_window", server.BackupWindow)
 
+		d.Set("delete_protection", server.Protection.Delete)
+		d.Set("rebuild_protection", server.Protection.Rebuild)
+
+		// check if an iso is attached
+		if server.ISO != nil {
+			d.Set("iso", strconv.Itoa(server.ISO.ID))
+		} else {
+			d.Set("iso", "")
+		}
+
 		// check if backup is enabled or disabled
 		if len(server.BackupWindow) > 0 {
 			d.Set("backup", true)
@@ -291,160 +532,201 @@ func resourceHcloudServerRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceHcloudServerUpdate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
 	// convert id from string to int
-	id, err := strconv.Atoi(d.Id())
+	id, err := parseID(d.Id())
 	if err != nil {
 		return err
 	}
 
 	// get server object
-	server, _, err := m.(*hcloud.Client).Server.GetByID(context.Background(), id)
+	server, _, err := m.(*Config).Client.Server.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if server != nil {
-		// update server name, when necessary
-		if server.Name != d.Get("name").(string) {
-			server, _, err = m.(*hcloud.Client).Server.Update(context.Background(), server, hcloud.ServerUpdateOpts{
+		// update server name and labels, when necessary
+		if server.Name != d.Get("name").(string) || d.HasChange("labels") || d.HasChange("default_labels") {
+			server, _, err = m.(*Config).Client.Server.Update(ctx, server, hcloud.ServerUpdateOpts{
 				Name: d.Get("name").(string),
+				Labels: m.(*Config).mergeLabels(d.Get("labels").(map[string]interface{})),
 			})
 			if err != nil {
 				return err
 			}
 		}
 
-		// update IPv4 DNS PTR, when necessary
-		if server.PublicNet.IPv4.DNSPtr != d.Get("ipv4_ptr").(string) {
-			ptr := d.Get("ipv4_ptr").(string)
+		// lift protection first, so it doesn't block the following changes
+		protectionChanged := d.HasChange("delete_protection") || d.HasChange("rebuild_protection")
+		protect := d.Get("delete_protection").(bool) || d.Get("rebuild_protection").(bool)
+		if protectionChanged && !protect {
+			err = resourceHcloudServerSetProtection(ctx, m.(*Config), server, false, false)
+			if err != nil {
+				return err
+			}
+		}
 
-			// send dns ptr change request
-			act, _, err := m.(*hcloud.Client).Server.ChangeDNSPtr(context.Background(), server, server.PublicNet.IPv4.IP.String(), &ptr)
+		// rebuild server in place, if the image or a trigger changed
+		o, n := d.GetChange("rebuild_triggers")
+		if d.HasChange("image") || rebuildTriggersChanged(o.(map[string]interface{}), n.(map[string]interface{})) {
+			img, err := getImage(ctx, m.(*Config).Client, d.Get("image").(string))
+			if err != nil {
+				return err
+			}
+
+			act, pw, err := rebuildServer(ctx, m.(*Config), server, img.ID)
 			if err != nil {
 				return err
 			}
 
 			// wait for action to finish and check for errors
-			_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-			err = <-errch
+			err = m.(*Config).waitForAction(ctx, act)
 			if err != nil {
 				return err
 			}
+
+			d.Set("root_pw", pw)
 		}
 
-		// upgrade server type, if necessary
-		if server.ServerType.ID != d.Get("server_type").(int) {
-			restart := false
+		// update IPv4 DNS PTR, when necessary
+		if server.PublicNet.IPv4.DNSPtr != d.Get("ipv4_ptr").(string) {
+			ptr := d.Get("ipv4_ptr").(string)
 
-			// check if server is running
-			if server.Status != "off" {
-				// restart server afterwards
-				restart = true
+			err = changeServerDNSPtr(ctx, m.(*Config), server, server.PublicNet.IPv4.IP.String(), &ptr)
+			if err != nil {
+				return err
+			}
+		}
 
-				// try acpi shutdown
-				act, _, err := m.(*hcloud.Client).Server.Shutdown(context.Background(), server)
+		// update IPv6 DNS PTRs, when necessary
+		err = resourceHcloudServerChangeIPv6Ptr(ctx, m.(*Config), server, d.Get("ipv6_ptr").(map[string]interface{}))
+		if err != nil {
+			return err
+		}
+
+		// attach or detach iso, if necessary
+		var isoid string
+		if server.ISO != nil {
+			isoid = strconv.Itoa(server.ISO.ID)
+		}
+		if isoid != d.Get("iso").(string) {
+			// detach the currently attached iso first
+			if server.ISO != nil {
+				act, _, err := m.(*Config).Client.Server.DetachISO(ctx, server)
 				if err != nil {
 					return err
 				}
 
 				// wait for action to finish and check for errors
-				_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-				err = <-errch
+				err = m.(*Config).waitForAction(ctx, act)
 				if err != nil {
 					return err
 				}
-
-				// try for 5 minutes if server is powered off
-				for i := 0; i < 10; i++ {
-					time.Sleep(30 * time.Second)
-
-					server, _, err := m.(*hcloud.Client).Server.GetByID(context.Background(), id)
-					if err != nil {
-						return err
-					}
-
-					// Check that server still exists
-					if server == nil {
-						d.SetId("");
-						return nil
-					}
-
-					// Check server status
-					if server.Status == "off" {
-						break
-					}
-				}
 			}
 
-			// check if server is still running
-			if server.Status != "off" {
-				// power off
-				act, _, err := m.(*hcloud.Client).Server.Poweroff(context.Background(), server)
+			// attach new iso, if set
+			if cid := d.Get("iso").(string); len(cid) > 0 {
+				newid, err := parseID(cid)
 				if err != nil {
 					return err
 				}
 
-				// wait for action to finish and check for errors
-				_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-				err = <-errch
+				err = resourceHcloudServerAttachISO(ctx, m.(*Config), server, newid)
 				if err != nil {
 					return err
 				}
 			}
+		}
+
+		// get the configured server type object
+		st, err := getServerType(ctx, m.(*Config).Client, d.Get("server_type").(string))
+		if err != nil {
+			return err
+		}
+
+		// upgrade server type, if necessary
+		if server.ServerType.ID != st.ID {
+			// restart server afterwards, if it is running and supposed to keep running
+			restart := server.Status != hcloud.ServerStatusOff && d.Get("power_state").(string) == serverPowerStateOn
+
+			// check if server is running
+			if server.Status != hcloud.ServerStatusOff {
+				server, err = resourceHcloudServerShutdown(ctx, m.(*Config), server, getServerShutdownPolicy(d, "resize_shutdown"))
+				if err != nil {
+					return fmt.Errorf("Error shutting down server %d to change its type: %s", id, err)
+				}
+
+				// check that server still exists
+				if server == nil {
+					d.SetId("")
+					return nil
+				}
+			}
 
 			// send server change type request
-			act, _, err := m.(*hcloud.Client).Server.ChangeType(context.Background(), server, hcloud.ServerChangeTypeOpts{
-				ServerType: &hcloud.ServerType{
-					ID: d.Get("server_type").(int),
-				},
+			act, _, err := m.(*Config).Client.Server.ChangeType(ctx, server, hcloud.ServerChangeTypeOpts{
+				ServerType: st,
 				UpgradeDisk: d.Get("upgrade_disk").(bool),
 			})
 			if err != nil {
-				return nil
+				return err
 			}
 
 			// wait for action to finish and check for errors
-			_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-			err = <-errch
+			err = m.(*Config).waitForAction(ctx, act)
 			if err != nil {
 				return err
 			}
 
+			// the disk only grows with the server type, if it is upgraded
+			if d.Get("upgrade_disk").(bool) {
+				d.Set("disk_size", st.Disk)
+			}
+
 			// start server, if it was running beforehand
 			if restart {
 				// power on request
-				act, _, err := m.(*hcloud.Client).Server.Poweron(context.Background(), server)
+				act, _, err := m.(*Config).Client.Server.Poweron(ctx, server)
 				if err != nil {
 					return err
 				}
 
 				// wait for action to finish and check for errors
-				_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-				err = <-errch
+				err = m.(*Config).waitForAction(ctx, act)
 				if err != nil {
 					return err
 				}
 			}
 		}
 
+		// power server on or off, if necessary
+		if state, ok := d.GetOk("power_state"); ok {
+			err = resourceHcloudServerSetPowerState(ctx, m.(*Config), id, state.(string))
+			if err != nil {
+				return err
+			}
+		}
+
 		// enable backup and/or update backup window, if necessary
 		if d.Get("backup").(bool) && (d.Get("backup_window").(string) != server.BackupWindow || len(server.BackupWindow) < 1) {
 			// send enable backup action
-			act, _, err := m.(*hcloud.Client).Server.EnableBackup(context.Background(), server, d.Get("backup_window").(string))
+			act, _, err := m.(*Config).Client.Server.EnableBackup(ctx, server, d.Get("backup_window").(string))
 			if err != nil {
 				return err
 			}
 
 			// wait for action to finish and check for errors
-			_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-			err = <-errch
+			err = m.(*Config).waitForAction(ctx, act)
 			if err != nil {
 				return err
 			}
 
 			// get randomly assigned backup window, if none was set
 			if len(d.Get("backup_window").(string)) < 1 {
-				server, _, err := m.(*hcloud.Client).Server.GetByID(context.Background(), id)
+				server, _, err := m.(*Config).Client.Server.GetByID(ctx, id)
 				if err != nil {
 					return err
 				}
@@ -462,14 +744,21 @@ func resourceHcloudServerUpdate(d *schema.ResourceData, m interface{}) error {
 		// disable backup, if neccessary
 		if !d.Get("backup").(bool) {
 			// send disable backup action
-			act, _, err := m.(*hcloud.Client).Server.DisableBackup(context.Background(), server)
+			act, _, err := m.(*Config).Client.Server.DisableBackup(ctx, server)
 			if err != nil {
 				return err
 			}
 
 			// wait for action to finish and check for errors
-			_, errch := m.(*hcloud.Client).Action.WatchProgress(context.Background(), act)
-			err = <-errch
+			err = m.(*Config).waitForAction(ctx, act)
+			if err != nil {
+				return err
+			}
+		}
+
+		// enable protection last
+		if protectionChanged && protect {
+			err = resourceHcloudServerSetProtection(ctx, m.(*Config), server, d.Get("delete_protection").(bool), d.Get("rebuild_protection").(bool))
 			if err != nil {
 				return err
 			}
@@ -482,14 +771,17 @@ func resourceHcloudServerUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceHcloudServerDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
 	// convert if from string to int
-	id, err := strconv.Atoi(d.Id())
+	id, err := parseID(d.Id())
 	if err != nil {
 		return err
 	}
 
 	// get server object
-	server, _, err := m.(*hcloud.Client).Server.GetByID(context.Background(), id)
+	server, _, err := m.(*Config).Client.Server.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -500,9 +792,598 @@ func resourceHcloudServerDelete(d *schema.ResourceData, m interface{}) error {
 		return nil
 	}
 
+	// fail before touching the server, the api would reject the delete request anyway
+	if server.Protection.Delete {
+		return fmt.Errorf("Server %d (%s) is protected against deletion, set delete_protection = false and apply before destroying it", id, server.Name)
+	}
+
+	// try acpi shutdown first, if requested
+	if d.Get("shutdown_before_deletion").(bool) && server.Status != hcloud.ServerStatusOff {
+		act, _, err := m.(*Config).Client.Server.Shutdown(ctx, server)
+		if err != nil {
+			return err
+		}
+
+		// wait for action to finish and check for errors
+		err = m.(*Config).waitForAction(ctx, act)
+		if err != nil {
+			return err
+		}
+
+		// give the server 5 minutes to power off, the delete request powers it off otherwise
+		sctx, scancel := context.WithTimeout(ctx, 5 * time.Minute)
+		_, err = waitForServer(sctx, m.(*Config), id, "off", func(server *hcloud.Server) bool {
+			return server == nil || server.Status == hcloud.ServerStatusOff
+		})
+		scancel()
+		if err != nil && ctx.Err() != nil {
+			return err
+		}
+		if err != nil {
+			log.Printf("[WARN] Server %d didn't shut down, deleting it anyway: %s", id, err)
+		}
+	}
+
+	// take a final snapshot, the server is only deleted, if it succeeds
+	if v := d.Get("snapshot_on_destroy").([]interface{}); len(v) > 0 && v[0] != nil {
+		block := v[0].(map[string]interface{})
+
+		img, err := resourceHcloudServerFinalSnapshot(ctx, m.(*Config), server, block)
+		if err != nil {
+			return fmt.Errorf("Error taking the final snapshot of server %d, it wasn't deleted: %s", id, err)
+		}
+		log.Printf("[INFO] Final snapshot of server %d (%s): image %d", id, server.Name, img.ID)
+
+		if path := block["manifest"].(string); len(path) > 0 {
+			err = appendSnapshotManifest(path, snapshotManifestEntry{
+				ServerID: id,
+				ServerName: server.Name,
+				ImageID: img.ID,
+				Description: img.Description,
+				Created: img.Created.String(),
+			})
+			if err != nil {
+				return fmt.Errorf("Error writing image %d of the final snapshot of server %d to %s, the server wasn't deleted: %s", img.ID, id, path, err)
+			}
+		}
+	}
+
 	// send server delete request
-	_, err = m.(*hcloud.Client).Server.Delete(context.Background(), server)
-	return err
+	_, err = m.(*Config).Client.Server.Delete(ctx, server)
+	if err != nil {
+		return err
+	}
 
-	// hcloud go library currently doesn't support checking the action status
+	// hcloud go library doesn't return the delete action, so wait until the server is gone
+	_, err = waitForServer(ctx, m.(*Config), id, "deleted", func(server *hcloud.Server) bool {
+		return server == nil
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SetId("")
+	return nil
+}
+
+func resourceHcloudServerSetPowerState(ctx context.Context, config *Config, id int, state string) error {
+	// get current server state
+	server, _, err := config.Client.Server.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return nil
+	}
+
+	var act *hcloud.Action
+	switch state {
+	case serverPowerStateOn:
+		if server.Status != hcloud.ServerStatusOff {
+			return nil
+		}
+
+		// power on request
+		act, _, err = config.Client.Server.Poweron(ctx, server)
+		if err != nil {
+			return err
+		}
+
+	case serverPowerStateOff:
+		if server.Status == hcloud.ServerStatusOff {
+			return nil
+		}
+
+		// power off request
+		act, _, err = config.Client.Server.Poweroff(ctx, server)
+		if err != nil {
+			return err
+		}
+
+	case serverPowerStateOffGraceful:
+		if server.Status == hcloud.ServerStatusOff {
+			return nil
+		}
+
+		_, err = resourceHcloudServerShutdown(ctx, config, server, defaultServerShutdownPolicy)
+		return err
+	}
+
+	// wait for action to finish and check for errors
+	return config.waitForAction(ctx, act)
+}
+
+// serverShutdownPolicy defines how long to wait for an acpi shutdown and whether to power
+// the server off afterwards
+type serverShutdownPolicy struct {
+	Timeout           time.Duration
+	PollInterval      time.Duration
+	AllowHardPoweroff bool
+}
+
+// defaultServerShutdownPolicy tries an acpi shutdown for 5 minutes before powering off
+var defaultServerShutdownPolicy = serverShutdownPolicy{
+	Timeout:           5 * time.Minute,
+	PollInterval:      30 * time.Second,
+	AllowHardPoweroff: true,
+}
+
+// serverShutdownPolicySchema is the block, which configures a serverShutdownPolicy
+func serverShutdownPolicySchema() *schema.Schema {
+	return &schema.Schema{
+		Type: schema.TypeList,
+		Optional: true,
+		MaxItems: 1,
+		Elem: &schema.Resource{
+			Schema: map[string]*schema.Schema{
+				"timeout": &schema.Schema{
+					Type: schema.TypeInt,
+					Optional: true,
+					Default: 300,
+					ValidateFunc: validation.IntAtLeast(0),
+				},
+				"poll_interval": &schema.Schema{
+					Type: schema.TypeInt,
+					Optional: true,
+					Default: 30,
+					ValidateFunc: validation.IntAtLeast(1),
+				},
+				"allow_hard_poweroff": &schema.Schema{
+					Type: schema.TypeBool,
+					Optional: true,
+					Default: true,
+				},
+			},
+		},
+	}
+}
+
+// getServerShutdownPolicy returns the policy of the block key, or the default policy, if the
+// block isn't set
+func getServerShutdownPolicy(d *schema.ResourceData, key string) serverShutdownPolicy {
+	policy := defaultServerShutdownPolicy
+
+	if v, ok := d.GetOk(key); ok && len(v.([]interface{})) > 0 && v.([]interface{})[0] != nil {
+		block := v.([]interface{})[0].(map[string]interface{})
+		policy.Timeout = time.Duration(block["timeout"].(int)) * time.Second
+		policy.PollInterval = time.Duration(block["poll_interval"].(int)) * time.Second
+		policy.AllowHardPoweroff = block["allow_hard_poweroff"].(bool)
+	}
+
+	return policy
+}
+
+// resourceHcloudServerShutdown tries an acpi shutdown and powers the server off, if it is still
+// running after the policy's timeout and hard poweroff is allowed. It returns the server in its
+// new state or nil, if the server doesn't exist anymore.
+func resourceHcloudServerShutdown(ctx context.Context, config *Config, server *hcloud.Server, policy serverShutdownPolicy) (*hcloud.Server, error) {
+	// try acpi shutdown
+	act, _, err := config.Client.Server.Shutdown(ctx, server)
+	if err != nil {
+		return nil, err
+	}
+
+	// wait for action to finish and check for errors
+	err = config.waitForAction(ctx, act)
+	if err != nil {
+		return nil, err
+	}
+
+	// poll server status until it is powered off or the timeout is reached
+	deadline := time.Now().Add(policy.Timeout)
+	for server.Status != hcloud.ServerStatusOff && time.Now().Before(deadline) {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("Timeout while waiting for server %d to shut down: %s", server.ID, ctx.Err())
+		case <-time.After(policy.PollInterval):
+		}
+
+		server, _, err = config.Client.Server.GetByID(ctx, server.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		// check that server still exists
+		if server == nil {
+			return nil, nil
+		}
+	}
+
+	// check if server is still running
+	if server.Status != hcloud.ServerStatusOff {
+		if !policy.AllowHardPoweroff {
+			return nil, fmt.Errorf("Server %d is still %s %s after the ACPI shutdown and hard poweroff is not allowed", server.ID, server.Status, policy.Timeout)
+		}
+
+		// power off
+		act, _, err := config.Client.Server.Poweroff(ctx, server)
+		if err != nil {
+			return nil, err
+		}
+
+		// wait for action to finish and check for errors
+		err = config.waitForAction(ctx, act)
+		if err != nil {
+			return nil, err
+		}
+
+		server, _, err = config.Client.Server.GetByID(ctx, server.ID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return server, nil
+}
+
+// rebuildServer rebuilds the server with the image and returns the new root password, which
+// is empty if the image uses ssh keys. The hcloud go library drops the root password of the
+// rebuild response, so the request is sent directly.
+func rebuildServer(ctx context.Context, config *Config, server *hcloud.Server, image int) (*hcloud.Action, string, error) {
+	body, err := json.Marshal(hcloudschema.ServerActionRebuildRequest{
+		Image: image,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	// send rebuild request
+	req, err := config.Client.NewRequest(ctx, "POST", fmt.Sprintf("/servers/%d/actions/rebuild", server.ID), bytes.NewReader(body))
+	if err != nil {
+		return nil, "", err
+	}
+
+	var resp struct {
+		Action       hcloudschema.Action `json:"action"`
+		RootPassword *string             `json:"root_password"`
+	}
+	_, err = config.Client.Do(req, &resp)
+	if err != nil {
+		return nil, "", err
+	}
+
+	var pw string
+	if resp.RootPassword != nil {
+		pw = *resp.RootPassword
+	}
+
+	return hcloud.ActionFromSchema(resp.Action), pw, nil
+}
+
+// waitForServer polls the server every PollInterval until done returns true or ctx is done.
+// done is called with nil, when the server doesn't exist.
+func waitForServer(ctx context.Context, config *Config, id int, state string, done func(server *hcloud.Server) bool) (*hcloud.Server, error) {
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		server, _, err := config.Client.Server.GetByID(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("Timeout while waiting for server %d to be %s: %s", id, state, ctx.Err())
+			}
+			return nil, err
+		}
+
+		if done(server) {
+			return server, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return server, fmt.Errorf("Timeout while waiting for server %d to be %s, it is %s: %s", id, state, server.Status, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+func resourceHcloudServerAttachISO(ctx context.Context, config *Config, server *hcloud.Server, id int) error {
+	// get iso object
+	iso, _, err := config.Client.ISO.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if iso == nil {
+		return errors.New("ISO " + strconv.Itoa(id) + " not found")
+	}
+
+	// send attach iso request
+	act, _, err := config.Client.Server.AttachISO(ctx, server, iso)
+	if err != nil {
+		return err
+	}
+
+	// wait for action to finish and check for errors
+	return config.waitForAction(ctx, act)
+}
+
+// resourceHcloudServerFinalSnapshot takes the snapshot of the snapshot_on_destroy block and waits
+// until it's available. {{id}}, {{name}} and {{timestamp}} in the description are replaced.
+func resourceHcloudServerFinalSnapshot(ctx context.Context, config *Config, server *hcloud.Server, block map[string]interface{}) (*hcloud.Image, error) {
+	description := strings.NewReplacer(
+		"{{id}}", strconv.Itoa(server.ID),
+		"{{name}}", server.Name,
+		"{{timestamp}}", time.Now().UTC().Format(time.RFC3339),
+	).Replace(block["description"].(string))
+
+	// send create image request
+	result, _, err := config.Client.Server.CreateImage(ctx, server, &hcloud.ServerCreateImageOpts{
+		Type: hcloud.ImageTypeSnapshot,
+		Description: &description,
+		Labels: config.mergeLabels(block["labels"].(map[string]interface{})),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// wait for action to finish and check for errors
+	err = config.waitForAction(ctx, result.Action)
+	if err != nil {
+		return nil, err
+	}
+
+	return waitForImage(ctx, config, result.Image.ID)
+}
+
+// resourceHcloudServerSetProtection changes the protection of a server and waits for the
+// action to finish
+func resourceHcloudServerSetProtection(ctx context.Context, config *Config, server *hcloud.Server, protectDelete bool, protectRebuild bool) error {
+	// send change protection request
+	act, _, err := config.Client.Server.ChangeProtection(ctx, server, hcloud.ServerChangeProtectionOpts{
+		Delete:  hcloud.Bool(protectDelete),
+		Rebuild: hcloud.Bool(protectRebuild),
+	})
+	if err != nil {
+		return err
+	}
+
+	// wait for action to finish and check for errors
+	return config.waitForAction(ctx, act)
+}
+
+func resourceHcloudServerCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	// the api only accepts the same value for both protections
+	if d.Get("delete_protection").(bool) != d.Get("rebuild_protection").(bool) {
+		return errors.New("delete_protection and rebuild_protection have to be set to the same value")
+	}
+
+	// a protected server can't be rebuilt
+	o, n := d.GetChange("rebuild_triggers")
+	imageChanged := referenceChanged(d, "image")
+	rebuild := rebuildTriggersChanged(o.(map[string]interface{}), n.(map[string]interface{})) || (imageChanged && d.Get("rebuild_on_image_change").(bool))
+	if len(d.Id()) > 0 && rebuild && d.Get("rebuild_protection").(bool) {
+		return errors.New("the server is protected against rebuilds, set rebuild_protection = false to rebuild it")
+	}
+
+	// a new image replaces the server, unless it should be rebuilt in place
+	if len(d.Id()) > 0 && imageChanged && !d.Get("rebuild_on_image_change").(bool) {
+		err := d.ForceNew("image")
+		if err != nil {
+			return err
+		}
+	}
+
+	// the IPv6 network is unknown until the server is created
+	network := d.Get("ipv6_network").(string)
+	if len(network) < 1 {
+		return nil
+	}
+
+	_, ipnet, err := net.ParseCIDR(network)
+	if err != nil {
+		return err
+	}
+
+	// check that all IPv6 PTRs are inside the server's network
+	for ip := range d.Get("ipv6_ptr").(map[string]interface{}) {
+		if !ipnet.Contains(net.ParseIP(ip)) {
+			return fmt.Errorf("ipv6_ptr: %s is not part of the server's IPv6 network %s", ip, network)
+		}
+	}
+
+	return nil
+}
+
+// rebuildTriggersChanged reports whether a trigger changed its value. Added or removed
+// triggers don't rebuild the server, so adding the map to an existing server keeps its disk.
+func rebuildTriggersChanged(old, new map[string]interface{}) bool {
+	for k, v := range new {
+		if ov, ok := old[k]; ok && ov != v {
+			return true
+		}
+	}
+	return false
+}
+
+// resourceHcloudServerReferencesCustomizeDiff looks up the referenced objects during plan,
+// so unknown names fail early and the ids are known before apply
+func resourceHcloudServerReferencesCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	ctx := context.Background()
+	client := m.(*Config).Client
+
+	resolvers := []struct {
+		key     string
+		resolve func(ref string) (int, error)
+	}{
+		{"server_type", func(ref string) (int, error) {
+			st, err := getServerType(ctx, client, ref)
+			if err != nil {
+				return 0, err
+			}
+			return st.ID, nil
+		}},
+		{"image", func(ref string) (int, error) {
+			img, err := getImage(ctx, client, ref)
+			if err != nil {
+				return 0, err
+			}
+			return img.ID, nil
+		}},
+		{"location", func(ref string) (int, error) {
+			loc, err := getLocation(ctx, client, ref)
+			if err != nil {
+				return 0, err
+			}
+			return loc.ID, nil
+		}},
+		{"datacenter", func(ref string) (int, error) {
+			dc, err := getDatacenter(ctx, client, ref)
+			if err != nil {
+				return 0, err
+			}
+			return dc.ID, nil
+		}},
+	}
+
+	for _, r := range resolvers {
+		if !referenceChanged(d, r.key) {
+			continue
+		}
+
+		// the reference is resolved during apply
+		if !d.NewValueKnown(r.key) {
+			err := d.SetNewComputed(r.key + "_id")
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		ref := d.Get(r.key).(string)
+		if len(ref) < 1 {
+			continue
+		}
+
+		id, err := r.resolve(ref)
+		if err != nil {
+			return fmt.Errorf("%s: %s", r.key, err)
+		}
+
+		err = d.SetNew(r.key + "_id", strconv.Itoa(id))
+		if err != nil {
+			return err
+		}
+	}
+
+	if !referenceListChanged(d, "ssh_keys", "ssh_key_ids") {
+		return nil
+	}
+	if !d.NewValueKnown("ssh_keys") {
+		return d.SetNewComputed("ssh_key_ids")
+	}
+
+	refs := d.Get("ssh_keys").([]interface{})
+	ids := make([]string, len(refs))
+	for i, v := range refs {
+		if !d.NewValueKnown(fmt.Sprintf("ssh_keys.%d", i)) {
+			return d.SetNewComputed("ssh_key_ids")
+		}
+
+		key, err := getSSHKey(ctx, client, v.(string))
+		if err != nil {
+			return fmt.Errorf("ssh_keys: %s", err)
+		}
+		ids[i] = strconv.Itoa(key.ID)
+	}
+
+	return d.SetNew("ssh_key_ids", ids)
+}
+
+// resourceHcloudServerDiskCustomizeDiff checks during plan, that the disk fits into the new
+// server type. A disk which was upgraded can never shrink again.
+func resourceHcloudServerDiskCustomizeDiff(d *schema.ResourceDiff, m interface{}) error {
+	if len(d.Id()) < 1 || !referenceChanged(d, "server_type") || !d.NewValueKnown("server_type") {
+		return nil
+	}
+
+	// get target server type object
+	st, err := getServerType(context.Background(), m.(*Config).Client, d.Get("server_type").(string))
+	if err != nil {
+		return err
+	}
+
+	disk := d.Get("disk_size").(int)
+	if st.Disk < disk {
+		if d.Get("disk_downgrade").(string) == serverDiskDowngradeReplace {
+			return d.ForceNew("server_type")
+		}
+		return fmt.Errorf("server_type: the disk of server type %s (%d GB) is smaller than the server's disk (%d GB), set disk_downgrade = \"%s\" to replace the server", st.Name, st.Disk, disk, serverDiskDowngradeReplace)
+	}
+
+	// the disk grows with the server type
+	if d.Get("upgrade_disk").(bool) && st.Disk > disk {
+		return d.SetNew("disk_size", st.Disk)
+	}
+
+	return nil
+}
+
+func validateIPv6PtrMap(v interface{}, k string) (ws []string, es []error) {
+	for ip := range v.(map[string]interface{}) {
+		parsed := net.ParseIP(ip)
+		if parsed == nil || parsed.To4() != nil {
+			es = append(es, fmt.Errorf("%s: %q is not a valid IPv6 address", k, ip))
+		} else if parsed.String() != ip {
+			// the api returns canonical addresses, other forms would show up in every plan
+			es = append(es, fmt.Errorf("%s: %q is not in canonical form, use %q", k, ip, parsed.String()))
+		}
+	}
+	return
+}
+
+func resourceHcloudServerChangeIPv6Ptr(ctx context.Context, config *Config, server *hcloud.Server, ptrs map[string]interface{}) error {
+	// the api stores addresses in their canonical form
+	want := make(map[string]string, len(ptrs))
+	for ip, ptr := range ptrs {
+		parsed := net.ParseIP(ip)
+		if server.PublicNet.IPv6.Network != nil && !server.PublicNet.IPv6.Network.Contains(parsed) {
+			return fmt.Errorf("ipv6_ptr: %s is not part of the server's IPv6 network %s", ip, server.PublicNet.IPv6.Network.String())
+		}
+		want[parsed.String()] = ptr.(string)
+	}
+
+	// collect addresses to set or change
+	changes := make(map[string]*string)
+	for ip, ptr := range want {
+		if server.PublicNet.IPv6.DNSPtr[ip] != ptr {
+			sptr := ptr
+			changes[ip] = &sptr
+		}
+	}
+
+	// reset addresses which are no longer configured
+	for ip := range server.PublicNet.IPv6.DNSPtr {
+		if _, ok := want[ip]; !ok {
+			changes[ip] = nil
+		}
+	}
+
+	for ip, ptr := range changes {
+		err := changeServerDNSPtr(ctx, config, server, ip, ptr)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
 }
\ No newline at end of file
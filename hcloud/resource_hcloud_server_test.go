@@ -0,0 +1,134 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/config"
+	"github.com/hashicorp/terraform/terraform"
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newServerTestConfig returns a config, whose client talks to a stub api. The stub knows
+// image 3, server type cx11 and ssh key my-key and records all requests.
+func newServerTestConfig(t *testing.T) (*Config, *[]string) {
+	var requests []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests = append(requests, r.Method + " " + r.URL.Path)
+
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == "GET" && r.URL.Path == "/images/3":
+			w.Write([]byte(`{"image":{"id":3,"name":"ubuntu-18.04","type":"system","status":"available"}}`))
+			return
+		case r.Method == "GET" && r.URL.Path == "/server_types" && r.URL.Query().Get("name") == "cx11":
+			w.Write([]byte(`{"server_types":[{"id":1,"name":"cx11","disk":20}]}`))
+			return
+		case r.Method == "GET" && r.URL.Path == "/ssh_keys" && r.URL.Query().Get("name") == "my-key":
+			w.Write([]byte(`{"ssh_keys":[{"id":5,"name":"my-key"}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":"not_found","message":"not found"}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	return &Config{
+		Client: hcloud.NewClient(
+			hcloud.WithEndpoint(server.URL),
+			hcloud.WithToken("test"),
+		),
+		PollInterval: time.Millisecond,
+	}, &requests
+}
+
+func newResourceConfig(t *testing.T, raw map[string]interface{}) *terraform.ResourceConfig {
+	c, err := config.NewRawConfig(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return terraform.NewResourceConfig(c)
+}
+
+func serverTestState() *terraform.InstanceState {
+	return &terraform.InstanceState{
+		ID: "42",
+		Attributes: map[string]string{
+			"id":                      "42",
+			"name":                    "test",
+			"server_type":             "cx11",
+			"server_type_id":          "1",
+			"image":                   "debian-9",
+			"image_id":                "2",
+			"datacenter":              "fsn1-dc8",
+			"datacenter_id":           "4",
+			"location":                "fsn1",
+			"location_id":             "1",
+			"ssh_keys.#":              "1",
+			"ssh_keys.0":              "my-key",
+			"ssh_key_ids.#":           "1",
+			"ssh_key_ids.0":           "5",
+			"disk_size":               "20",
+			"upgrade_disk":            "true",
+			"disk_downgrade":          serverDiskDowngradeFail,
+			"rebuild_on_image_change": "false",
+			"backup":                  "false",
+			"delete_protection":       "false",
+			"rebuild_protection":      "false",
+		},
+		Meta: map[string]interface{}{
+			"schema_version": "1",
+		},
+	}
+}
+
+func TestResourceHcloudServerDiffReferenceByID(t *testing.T) {
+	config, requests := newServerTestConfig(t)
+
+	// the state records the names, the config references the same objects by id
+	diff, err := resourceHcloudServer().Diff(serverTestState(), newResourceConfig(t, map[string]interface{}{
+		"name":        "test",
+		"server_type": "1",
+		"image":       "2",
+		"ssh_keys":    []interface{}{"5"},
+	}), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff != nil {
+		if diff.RequiresNew() {
+			t.Errorf("expected no replacement, got %#v", diff.Attributes)
+		}
+		for _, k := range []string{"server_type", "server_type_id", "image", "image_id", "ssh_keys.0", "ssh_key_ids.0"} {
+			if attr, ok := diff.Attributes[k]; ok {
+				t.Errorf("expected no diff of %s, got %#v", k, attr)
+			}
+		}
+	}
+	if len(*requests) > 0 {
+		t.Errorf("expected no api requests, got %v", *requests)
+	}
+}
+
+func TestResourceHcloudServerDiffImageChange(t *testing.T) {
+	config, _ := newServerTestConfig(t)
+
+	diff, err := resourceHcloudServer().Diff(serverTestState(), newResourceConfig(t, map[string]interface{}{
+		"name":        "test",
+		"server_type": "cx11",
+		"image":       "3",
+		"ssh_keys":    []interface{}{"my-key"},
+	}), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if diff == nil || !diff.RequiresNew() {
+		t.Fatalf("expected a replacement, got %#v", diff)
+	}
+	if attr := diff.Attributes["image_id"]; attr == nil || attr.New != "3" {
+		t.Errorf("expected image_id 3, got %#v", attr)
+	}
+}
@@ -0,0 +1,291 @@
+package hcloud
+
+import (
+	"github.com/hashicorp/terraform/helper/customdiff"
+	"github.com/hashicorp/terraform/helper/schema"
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	"context"
+	"strconv"
+	"time"
+	"errors"
+	"fmt"
+	"log"
+)
+
+func resourceHcloudSnapshot() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceHcloudSnapshotCreate,
+		Read:   resourceHcloudSnapshotRead,
+		Update: resourceHcloudSnapshotUpdate,
+		Delete: resourceHcloudSnapshotDelete,
+
+		Importer: &schema.ResourceImporter{
+			State: resourceHcloudSnapshotImport,
+		},
+
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		CustomizeDiff: customdiff.All(
+			readOnlyCustomizeDiff,
+			labelsCustomizeDiff,
+		),
+
+		Schema: map[string]*schema.Schema{
+			"server": &schema.Schema{
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"description": &schema.Schema{
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"labels": &schema.Schema{
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				ValidateFunc: validateLabels,
+			},
+			"default_labels": &schema.Schema{
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
+			"image_size": &schema.Schema{
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"disk_size": &schema.Schema{
+				Type:     schema.TypeFloat,
+				Computed: true,
+			},
+			"created": &schema.Schema{
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"shutdown": serverShutdownPolicySchema(),
+			"offline": &schema.Schema{
+				Type:     schema.TypeBool,
+				Computed: true,
+			},
+		},
+	}
+}
+
+func resourceHcloudSnapshotImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	err := resourceHcloudSnapshotRead(d, meta)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return []*schema.ResourceData{d}, nil
+}
+
+func resourceHcloudSnapshotCreate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	sid, err := parseID(d.Get("server").(string))
+	if err != nil {
+		return err
+	}
+
+	// get server object
+	server, _, err := m.(*Config).Client.Server.GetByID(ctx, sid)
+	if err != nil {
+		return err
+	}
+	if server == nil {
+		return errors.New("Server not found")
+	}
+
+	// shut the server down first, if requested, so the disk is consistent
+	restart := false
+	if len(d.Get("shutdown").([]interface{})) > 0 && server.Status != hcloud.ServerStatusOff {
+		restart = server.Status == hcloud.ServerStatusRunning
+
+		server, err = resourceHcloudServerShutdown(ctx, m.(*Config), server, getServerShutdownPolicy(d, "shutdown"))
+		if err != nil {
+			// don't leave the server off, if it was powered off without a snapshot
+			if restart {
+				resourceHcloudServerSetPowerState(ctx, m.(*Config), sid, serverPowerStateOn)
+			}
+			return fmt.Errorf("Error shutting down server %d for the snapshot: %s", sid, err)
+		}
+		if server == nil {
+			return errors.New("Server not found")
+		}
+	}
+	d.Set("offline", server.Status == hcloud.ServerStatusOff)
+
+	// send create image request
+	description := d.Get("description").(string)
+	result, _, err := m.(*Config).Client.Server.CreateImage(ctx, server, &hcloud.ServerCreateImageOpts{
+		Type: hcloud.ImageTypeSnapshot,
+		Description: &description,
+		Labels: m.(*Config).mergeLabels(d.Get("labels").(map[string]interface{})),
+	})
+	if err == nil {
+		// the image exists from now on, so it's destroyed, if waiting fails
+		d.SetId(strconv.Itoa(result.Image.ID))
+
+		// wait for action to finish and check for errors
+		err = m.(*Config).waitForAction(ctx, result.Action)
+		if err != nil {
+			err = fmt.Errorf("Error creating snapshot of server %d: %s", sid, err)
+		}
+	}
+
+	// power the server back on, even if the snapshot failed
+	if restart {
+		perr := resourceHcloudServerSetPowerState(ctx, m.(*Config), sid, serverPowerStateOn)
+		if perr != nil && err == nil {
+			err = fmt.Errorf("Error powering on server %d after the snapshot: %s", sid, perr)
+		} else if perr != nil {
+			log.Printf("[WARN] Error powering on server %d after the snapshot: %s", sid, perr)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	_, err = waitForImage(ctx, m.(*Config), result.Image.ID)
+	if err != nil {
+		return err
+	}
+
+	return resourceHcloudSnapshotRead(d, m)
+}
+
+func resourceHcloudSnapshotRead(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	id, err := parseID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// get image object
+	img, _, err := m.(*Config).Client.Image.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if img != nil {
+		// the server is gone, if it was deleted after the snapshot was taken
+		if img.CreatedFrom != nil {
+			d.Set("server", strconv.Itoa(img.CreatedFrom.ID))
+		}
+		d.Set("description", img.Description)
+
+		labels, defaults := m.(*Config).splitLabels(img.Labels, d.Get("labels").(map[string]interface{}))
+		d.Set("labels", labels)
+		d.Set("default_labels", defaults)
+		d.Set("image_size", img.ImageSize)
+		d.Set("disk_size", img.DiskSize)
+		d.Set("created", img.Created.String())
+	} else {
+		d.SetId("")
+	}
+
+	return nil
+}
+
+func resourceHcloudSnapshotUpdate(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	id, err := parseID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// get image object
+	img, _, err := m.(*Config).Client.Image.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if img == nil {
+		d.SetId("")
+		return nil
+	}
+
+	if d.HasChange("description") || d.HasChange("labels") || d.HasChange("default_labels") {
+		description := d.Get("description").(string)
+		_, _, err = m.(*Config).Client.Image.Update(ctx, img, hcloud.ImageUpdateOpts{
+			Description: &description,
+			Labels: m.(*Config).mergeLabels(d.Get("labels").(map[string]interface{})),
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return resourceHcloudSnapshotRead(d, m)
+}
+
+func resourceHcloudSnapshotDelete(d *schema.ResourceData, m interface{}) error {
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	id, err := parseID(d.Id())
+	if err != nil {
+		return err
+	}
+
+	// get image object
+	img, _, err := m.(*Config).Client.Image.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if img != nil {
+		_, err = m.(*Config).Client.Image.Delete(ctx, img)
+		if err != nil {
+			return err
+		}
+	}
+
+	d.SetId("")
+	return nil
+}
+
+// waitForImage waits until the image is available
+func waitForImage(ctx context.Context, config *Config, id int) (*hcloud.Image, error) {
+	ticker := time.NewTicker(config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		img, _, err := config.Client.Image.GetByID(ctx, id)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, fmt.Errorf("Timeout while waiting for image %d to be available: %s", id, ctx.Err())
+			}
+			return nil, err
+		}
+		if img == nil {
+			return nil, fmt.Errorf("Image %d not found", id)
+		}
+
+		if img.Status == hcloud.ImageStatusAvailable {
+			return img, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return img, fmt.Errorf("Timeout while waiting for image %d to be available, it is %s: %s", id, img.Status, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
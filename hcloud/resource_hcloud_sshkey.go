@@ -1,10 +1,12 @@
 package hcloud
 
 import (
+	"github.com/hashicorp/terraform/helper/customdiff"
 	"github.com/hashicorp/terraform/helper/schema"
 	"github.com/hetznercloud/hcloud-go/hcloud"
 	"context"
 	"strconv"
+	"time"
 )
 
 func resourceHcloudSSHKey() *schema.Resource {
@@ -18,6 +20,17 @@ func resourceHcloudSSHKey() *schema.Resource {
 			State: resourceHcloudSSHKeyImport,
 		},
 
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+
+		CustomizeDiff: customdiff.All(
+			readOnlyCustomizeDiff,
+			labelsCustomizeDiff,
+		),
+
 		Schema: map[string]*schema.Schema{
 			"name": &schema.Schema{
 				Type:     schema.TypeString,
@@ -32,6 +45,21 @@ func resourceHcloudSSHKey() *schema.Resource {
 				Type: schema.TypeString,
 				Computed: true,
 			},
+			"labels": &schema.Schema{
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+				ValidateFunc: validateLabels,
+			},
+			"default_labels": &schema.Schema{
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Computed: true,
+			},
 		},
 	}
 }
@@ -47,9 +75,13 @@ func resourceHcloudSSHKeyImport(d *schema.ResourceData, meta interface{}) ([]*sc
 }
 
 func resourceHcloudSSHKeyCreate(d *schema.ResourceData, m interface{}) error {
-	key, _, err := m.(*hcloud.Client).SSHKey.Create(context.Background(), hcloud.SSHKeyCreateOpts{
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutCreate))
+	defer cancel()
+
+	key, _, err := m.(*Config).Client.SSHKey.Create(ctx, hcloud.SSHKeyCreateOpts{
 		Name: d.Get("name").(string),
 		PublicKey: d.Get("public_key").(string),
+		Labels: m.(*Config).mergeLabels(d.Get("labels").(map[string]interface{})),
 	})
 	if err != nil {
 		return err
@@ -62,12 +94,15 @@ func resourceHcloudSSHKeyCreate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceHcloudSSHKeyRead(d *schema.ResourceData, m interface{}) error {
-	id, err := strconv.Atoi(d.Id())
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutRead))
+	defer cancel()
+
+	id, err := parseID(d.Id())
 	if err != nil {
 		return err
 	}
 
-	key, _, err := m.(*hcloud.Client).SSHKey.GetByID(context.Background(), id)
+	key, _, err := m.(*Config).Client.SSHKey.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
@@ -76,6 +111,9 @@ func resourceHcloudSSHKeyRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("name", key.Name)
 		d.Set("public_key", key.PublicKey)
 		d.Set("fingerprint", key.Fingerprint)
+		labels, defaults := m.(*Config).splitLabels(key.Labels, d.Get("labels").(map[string]interface{}))
+		d.Set("labels", labels)
+		d.Set("default_labels", defaults)
 	} else {
 		d.SetId("")
 	}
@@ -84,20 +122,24 @@ func resourceHcloudSSHKeyRead(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceHcloudSSHKeyUpdate(d *schema.ResourceData, m interface{}) error {
-	id, err := strconv.Atoi(d.Id())
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutUpdate))
+	defer cancel()
+
+	id, err := parseID(d.Id())
 	if err != nil {
 		return err
 	}
 
-	key, _, err := m.(*hcloud.Client).SSHKey.GetByID(context.Background(), id)
+	key, _, err := m.(*Config).Client.SSHKey.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if key != nil {
-		if key.Name != d.Get("name").(string) {
-			key, _, err = m.(*hcloud.Client).SSHKey.Update(context.Background(), key, hcloud.SSHKeyUpdateOpts{
+		if key.Name != d.Get("name").(string) || d.HasChange("labels") || d.HasChange("default_labels") {
+			key, _, err = m.(*Config).Client.SSHKey.Update(ctx, key, hcloud.SSHKeyUpdateOpts{
 				Name: d.Get("name").(string),
+				Labels: m.(*Config).mergeLabels(d.Get("labels").(map[string]interface{})),
 			})
 			if err != nil {
 				return err
@@ -111,18 +153,21 @@ func resourceHcloudSSHKeyUpdate(d *schema.ResourceData, m interface{}) error {
 }
 
 func resourceHcloudSSHKeyDelete(d *schema.ResourceData, m interface{}) error {
-	id, err := strconv.Atoi(d.Id())
+	ctx, cancel := context.WithTimeout(context.Background(), d.Timeout(schema.TimeoutDelete))
+	defer cancel()
+
+	id, err := parseID(d.Id())
 	if err != nil {
 		return err
 	}
 
-	key, _, err := m.(*hcloud.Client).SSHKey.GetByID(context.Background(), id)
+	key, _, err := m.(*Config).Client.SSHKey.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	if key != nil {
-		_, err = m.(*hcloud.Client).SSHKey.Delete(context.Background(), key)
+		_, err = m.(*Config).Client.SSHKey.Delete(ctx, key)
 		return err
 	}
 
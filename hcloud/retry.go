@@ -0,0 +1,206 @@
+package hcloud
+
+import (
+	"github.com/hetznercloud/hcloud-go/hcloud"
+	hcloudschema "github.com/hetznercloud/hcloud-go/hcloud/schema"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// retryTransport retries requests the api rejected because of the rate limit, a lock
+// or a conflict. Server errors and connection errors are only retried for idempotent
+// requests, since the request might already have been processed.
+type retryTransport struct {
+	transport  http.RoundTripper
+	maxRetries int
+	maxWait    time.Duration
+
+	mu    sync.Mutex
+	reset time.Time // set, when the rate limit is used up
+}
+
+func newRetryTransport(maxRetries int, maxWait time.Duration) *retryTransport {
+	return &retryTransport{
+		transport:  http.DefaultTransport,
+		maxRetries: maxRetries,
+		maxWait:    maxWait,
+	}
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// keep the body, so it can be sent again
+	getBody := req.GetBody
+	if getBody == nil && req.Body != nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		getBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	for retries := 0; ; retries++ {
+		// don't run into the rate limit, if we know it's used up
+		err := t.sleep(req, t.untilReset())
+		if err != nil {
+			return nil, err
+		}
+
+		// a round tripper must not modify the original request
+		r := req.WithContext(req.Context())
+		if getBody != nil {
+			r.Body, err = getBody()
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		resp, err := t.transport.RoundTrip(r)
+		if resp != nil {
+			t.readRateLimit(resp)
+		}
+
+		if !t.retryable(req, resp, err) {
+			return resp, err
+		}
+		if retries >= t.maxRetries {
+			// hcloud-go retries rate limited requests without a limit, so they end with an error
+			if err == nil && resp.StatusCode == http.StatusTooManyRequests {
+				ioutil.ReadAll(resp.Body)
+				resp.Body.Close()
+				return nil, fmt.Errorf("Rate limit exceeded, %s %s was retried %d times", req.Method, req.URL.Path, t.maxRetries)
+			}
+			return resp, err
+		}
+
+		wait := t.backoff(retries)
+		if resp != nil {
+			if resp.StatusCode == http.StatusTooManyRequests {
+				if reset := t.untilReset(); reset > wait {
+					wait = reset
+				}
+			}
+
+			log.Printf("[DEBUG] %s %s returned %s, retry %d/%d in %s", req.Method, req.URL.Path, resp.Status, retries+1, t.maxRetries, wait)
+
+			// the response is thrown away
+			ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+		} else {
+			log.Printf("[DEBUG] %s %s failed: %s, retry %d/%d in %s", req.Method, req.URL.Path, err, retries+1, t.maxRetries, wait)
+		}
+
+		err = t.sleep(req, wait)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (t *retryTransport) retryable(req *http.Request, resp *http.Response, err error) bool {
+	if err == nil {
+		switch resp.StatusCode {
+		case http.StatusTooManyRequests:
+			// the api didn't process the request
+			return true
+		case http.StatusLocked, http.StatusConflict:
+			// permanent errors like protected or uniqueness_error share these status codes
+			switch errorCode(resp) {
+			case hcloud.ErrorCodeLocked, hcloud.ErrorCodeConflict, hcloud.ErrorCodeRateLimitExceeded:
+				return true
+			}
+			return false
+		}
+	}
+
+	// the request might have been processed, only repeat it if that's safe
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return err != nil || resp.StatusCode >= 500
+	}
+
+	return false
+}
+
+// errorCode returns the code of an error response, the body is kept for the caller
+func errorCode(resp *http.Response) hcloud.ErrorCode {
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = ioutil.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var errResp hcloudschema.ErrorResponse
+	if json.Unmarshal(body, &errResp) != nil {
+		return ""
+	}
+	return hcloud.ErrorCode(errResp.Error.Code)
+}
+
+func (t *retryTransport) backoff(retries int) time.Duration {
+	wait := time.Second << uint(retries)
+	if wait > t.maxWait || wait <= 0 {
+		wait = t.maxWait
+	}
+	return wait
+}
+
+func (t *retryTransport) readRateLimit(resp *http.Response) {
+	remaining, err := strconv.Atoi(resp.Header.Get("RateLimit-Remaining"))
+	if err != nil {
+		return
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if remaining > 0 {
+		t.reset = time.Time{}
+	} else {
+		t.reset = time.Unix(reset, 0)
+	}
+}
+
+func (t *retryTransport) untilReset() time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.reset.IsZero() {
+		return 0
+	}
+
+	wait := time.Until(t.reset)
+	if wait > t.maxWait {
+		wait = t.maxWait
+	}
+	return wait
+}
+
+func (t *retryTransport) sleep(req *http.Request, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
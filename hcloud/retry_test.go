@@ -0,0 +1,101 @@
+package hcloud
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRetryTestServer answers the requests with the given status codes and error codes, the
+// last answer is repeated
+func newRetryTestServer(t *testing.T, answers ...[2]interface{}) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		answer := answers[len(answers)-1]
+		if requests < len(answers) {
+			answer = answers[requests]
+		}
+		requests++
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(answer[0].(int))
+		if code := answer[1].(string); len(code) > 0 {
+			w.Write([]byte(`{"error":{"code":"` + code + `","message":"test"}}`))
+		} else {
+			w.Write([]byte(`{}`))
+		}
+	}))
+	return server, &requests
+}
+
+func TestRetryTransport(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		answers  [][2]interface{}
+		requests int
+		status   int
+		err      bool
+	}{
+		{"success", "GET", [][2]interface{}{{200, ""}}, 1, 200, false},
+		{"locked", "POST", [][2]interface{}{{423, "locked"}, {200, ""}}, 2, 200, false},
+		{"conflict", "POST", [][2]interface{}{{409, "conflict"}, {409, "conflict"}, {201, ""}}, 3, 201, false},
+		{"protected", "DELETE", [][2]interface{}{{423, "protected"}}, 1, 423, false},
+		{"uniqueness error", "POST", [][2]interface{}{{409, "uniqueness_error"}}, 1, 409, false},
+		{"invalid input", "POST", [][2]interface{}{{400, "invalid_input"}}, 1, 400, false},
+		{"rate limit", "GET", [][2]interface{}{{429, "rate_limit_exceeded"}, {200, ""}}, 2, 200, false},
+		{"rate limit exhausted", "GET", [][2]interface{}{{429, "rate_limit_exceeded"}}, 3, 0, true},
+		{"server error get", "GET", [][2]interface{}{{503, "service_error"}, {200, ""}}, 2, 200, false},
+		{"server error post", "POST", [][2]interface{}{{503, "service_error"}}, 1, 503, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, requests := newRetryTestServer(t, tt.answers...)
+			defer server.Close()
+
+			client := &http.Client{Transport: newRetryTransport(2, time.Millisecond)}
+			req, err := http.NewRequest(tt.method, server.URL, strings.NewReader(`{"name":"test"}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			resp, err := client.Do(req)
+			if tt.err != (err != nil) {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp != nil {
+				resp.Body.Close()
+				if resp.StatusCode != tt.status {
+					t.Errorf("expected status %d, got %d", tt.status, resp.StatusCode)
+				}
+			}
+			if *requests != tt.requests {
+				t.Errorf("expected %d requests, got %d", tt.requests, *requests)
+			}
+		})
+	}
+}
+
+func TestRetryTransportKeepsErrorBody(t *testing.T) {
+	server, _ := newRetryTestServer(t, [2]interface{}{409, "uniqueness_error"})
+	defer server.Close()
+
+	client := &http.Client{Transport: newRetryTransport(2, time.Millisecond)}
+	resp, err := client.Post(server.URL, "application/json", strings.NewReader(`{}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(body), "uniqueness_error") {
+		t.Errorf("expected the error body, got %q", body)
+	}
+}